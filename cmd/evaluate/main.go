@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/config"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	service "github.com/asifrahaman13/bhagabad_gita/internal/core/services"
+	"github.com/asifrahaman13/bhagabad_gita/internal/evaluation"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Usage:
+//
+//	go run ./cmd/evaluate -golden eval/golden.yaml -k 5 -out report.json
+//	go run ./cmd/evaluate -golden eval/golden.yaml -baseline report.json -tolerance 0.01
+//	go run ./cmd/evaluate -mode answers -golden eval/golden.yaml -templates spiritual-expert-v1,grounded-scholar-v1 -models llama3.1
+//
+// In retrieval mode the command exits with status 1 when a threshold or
+// baseline check fails.
+func main() {
+	conf, err := config.Load()
+	ErrorHandler(err)
+	mode := flag.String("mode", "retrieval", "what to evaluate: retrieval or answers")
+	goldenPath := flag.String("golden", "", "golden question set (.json, .yaml or .yml)")
+	k := flag.Uint64("k", 5, "number of passages to retrieve per question")
+	outPath := flag.String("out", "", "write the JSON report to this file instead of stdout")
+	baselinePath := flag.String("baseline", "", "previous JSON report to compare against")
+	tolerance := flag.Float64("tolerance", 0, "allowed drop below the baseline before failing")
+	minRecall := flag.Float64("min-recall", 0, "fail when mean recall@k is below this value")
+	minMRR := flag.Float64("min-mrr", 0, "fail when mean MRR is below this value")
+	minNDCG := flag.Float64("min-ndcg", 0, "fail when mean nDCG@k is below this value")
+	templates := flag.String("templates", service.DEFAULT_PROMPT_TEMPLATE.Name, "comma separated prompt templates to compare in answers mode")
+	models := flag.String("models", conf.LLM.Model, "comma separated generation models to compare in answers mode")
+	judgeModel := flag.String("judge-model", conf.LLM.JudgeModel, "model that scores answers in answers mode")
+	flag.Parse()
+
+	if *goldenPath == "" || *k == 0 || (*mode != "retrieval" && *mode != "answers") {
+		flag.Usage()
+		os.Exit(2)
+	}
+	set, err := evaluation.LoadGoldenSet(*goldenPath)
+	ErrorHandler(err)
+
+	qdrantService, err := service.NewQdrantService(conf.Qdrant)
+	ErrorHandler(err)
+	embeddingService := service.NewEmbeddingService(conf.Embedding)
+
+	if *mode == "answers" {
+		variants, err := answerVariants(*templates, *models)
+		ErrorHandler(err)
+		llm := service.NewLLMService(conf.LLM)
+		chat := service.InitializeChatService(llm, embeddingService, qdrantService, nil)
+		answer := func(ctx context.Context, question string, variant evaluation.Variant) (domain.Answer, error) {
+			generator := chat.WithGeneration(service.PROMPT_TEMPLATES[variant.PromptTemplate], variant.Model)
+			return generator.Answer(ctx, domain.AskData{Question: question})
+		}
+		complete := func(ctx context.Context, model string, prompt string) (string, error) {
+			return llm.Complete(ctx, model, prompt, "json")
+		}
+		report, err := evaluation.EvaluateAnswers(context.Background(), set, variants, answer, *judgeModel, complete)
+		ErrorHandler(err)
+		writeReport(report, *outPath)
+		for _, variant := range report.Variants {
+			fmt.Fprintf(os.Stderr, "%s/%s faithfulness=%.4f relevance=%.4f answered=%d failed=%d\n",
+				variant.PromptTemplate, variant.Model, variant.Faithfulness, variant.Relevance, variant.Answered, variant.Failed)
+		}
+		return
+	}
+
+	retrieve := func(ctx context.Context, question string, k uint64) ([]domain.VectorSearchResult, error) {
+		return qdrantService.Search(ctx, question, embeddingService, domain.SearchOptions{Limit: k})
+	}
+
+	report, err := evaluation.EvaluateRetrieval(context.Background(), set, *k, retrieve)
+	ErrorHandler(err)
+	writeReport(report, *outPath)
+	fmt.Fprintf(os.Stderr, "recall@%d=%.4f MRR=%.4f nDCG@%d=%.4f over %d questions\n",
+		*k, report.Mean.RecallAtK, report.Mean.MRR, *k, report.Mean.NDCGAtK, report.Questions)
+
+	var baseline *evaluation.RetrievalMetrics
+	if *baselinePath != "" {
+		data, err := os.ReadFile(*baselinePath)
+		ErrorHandler(err)
+		var previous evaluation.RetrievalReport
+		ErrorHandler(json.Unmarshal(data, &previous))
+		baseline = &previous.Mean
+	}
+	thresholds := evaluation.Thresholds{RecallAtK: *minRecall, MRR: *minMRR, NDCGAtK: *minNDCG}
+	if failures := evaluation.CheckRegression(report.Mean, thresholds, baseline, *tolerance); len(failures) > 0 {
+		for _, failure := range failures {
+			fmt.Fprintln(os.Stderr, "FAIL:", failure)
+		}
+		os.Exit(1)
+	}
+}
+
+// answerVariants crosses the requested templates with the requested models.
+func answerVariants(templates string, models string) ([]evaluation.Variant, error) {
+	var variants []evaluation.Variant
+	for _, template := range splitList(templates) {
+		if _, ok := service.PROMPT_TEMPLATES[template]; !ok {
+			known := make([]string, 0, len(service.PROMPT_TEMPLATES))
+			for name := range service.PROMPT_TEMPLATES {
+				known = append(known, name)
+			}
+			sort.Strings(known)
+			return nil, fmt.Errorf("unknown prompt template %q, expected one of %s", template, strings.Join(known, ", "))
+		}
+		for _, model := range splitList(models) {
+			variants = append(variants, evaluation.Variant{PromptTemplate: template, Model: model})
+		}
+	}
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("at least one template and one model are required")
+	}
+	return variants, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func writeReport(report interface{}, path string) {
+	data, err := json.MarshalIndent(report, "", "  ")
+	ErrorHandler(err)
+	data = append(data, '\n')
+	if path == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	ErrorHandler(os.WriteFile(path, data, 0644))
+}
+
+func ErrorHandler(err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/config"
+	"github.com/asifrahaman13/bhagabad_gita/internal/migrations"
+	"github.com/asifrahaman13/bhagabad_gita/internal/repository"
+	"os"
+)
+
+// Usage:
+//
+//	go run ./cmd/migrate up
+//	go run ./cmd/migrate down -steps 1
+//	go run ./cmd/migrate status
+func main() {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	steps := flags.Int("steps", 1, "number of migrations to roll back with down")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: migrate up | down [-steps N] | status")
+		flags.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	command := flag.Arg(0)
+	ErrorHandler(flags.Parse(flag.Args()[1:]))
+
+	conf, err := config.Load()
+	ErrorHandler(err)
+	ctx := context.Background()
+	conn, err := repository.Connect(ctx, conf.Mongo)
+	ErrorHandler(err)
+	defer conn.Disconnect(ctx)
+	migrator := migrations.NewMigrator(conn.Database(), migrations.MIGRATIONS)
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		report("Applied", applied, err)
+	case "down":
+		if *steps <= 0 {
+			ErrorHandler(fmt.Errorf("-steps must be positive"))
+		}
+		rolledBack, err := migrator.Down(ctx, *steps)
+		report("Rolled back", rolledBack, err)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		ErrorHandler(err)
+		data, err := json.MarshalIndent(statuses, "", "  ")
+		ErrorHandler(err)
+		fmt.Println(string(data))
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// report prints the migrations that ran before exiting on err.
+func report(action string, done []migrations.Migration, err error) {
+	for _, migration := range done {
+		fmt.Printf("%s migration %d: %s\n", action, migration.Version, migration.Description)
+	}
+	ErrorHandler(err)
+	if len(done) == 0 {
+		fmt.Println("Nothing to do")
+	}
+}
+
+func ErrorHandler(err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
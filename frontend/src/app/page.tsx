@@ -7,16 +7,30 @@ type PageSearch = {
 };
 
 type MessageType = {
-  clientId: string;
-  messageId: number;
+  id: string;
   payload: string;
   msgType: string;
 };
 
+type Envelope = {
+  v: number;
+  type: string;
+  id: string;
+  replyTo?: string;
+  data?: {
+    question?: string;
+    passages?: PageSearch[];
+    text?: string;
+    code?: string;
+    message?: string;
+  };
+};
+
+const PROTOCOL_VERSION = 1;
+
 const RealTimeUpdates = () => {
   const [messages, setMessages] = useState<MessageType[]>([]);
   const [message, setMessage] = useState("");
-  const [clientId, setClientId] = useState("");
   const messageIdRef = useRef<number>(0);
   const wsRef = useRef<WebSocket | null>(null);
 
@@ -28,27 +42,27 @@ const RealTimeUpdates = () => {
 
     websocket.onopen = () => {
       console.log("WebSocket is connected");
-      const id = Math.floor(Math.random() * 1000);
-      setClientId(id.toString());
     };
 
     websocket.onmessage = (evt) => {
-      const message = JSON.parse(evt.data);
+      const message: Envelope = JSON.parse(evt.data);
 
-      if (message.msgType === "status") {
-        setSource(message.payload);
+      if (message.type === "context") {
+        setSource(message.data?.passages ?? null);
       }
-      if (message.msgType === "server") {
+      if (message.type === "sentence") {
         setMessages((prevMessages) => [
           ...prevMessages,
           {
-            clientId: message.clientId,
-            messageId: message.messageId,
-            payload: message.payload,
+            id: message.id,
+            payload: message.data?.text ?? "",
             msgType: "server",
           },
         ]);
       }
+      if (message.type === "error") {
+        console.error(message.data?.code, message.data?.message);
+      }
     };
 
     websocket.onclose = () => {
@@ -62,22 +76,22 @@ const RealTimeUpdates = () => {
 
   const sendMessage = () => {
     if (wsRef.current) {
+      const newMessageId = messageIdRef.current + 1;
+      messageIdRef.current = newMessageId;
+      const id = `ask-${newMessageId}`;
       setMessages((prevMessages) => [
         ...prevMessages,
         {
-          clientId: clientId,
-          messageId: 0,
+          id: id,
           payload: message,
           msgType: "client",
         },
       ]);
-      const newMessageId = messageIdRef.current + 1;
-      messageIdRef.current = newMessageId;
-      const messageSend = {
-        clientId: clientId,
-        messageId: newMessageId,
-        payload: message,
-        msgType: "client",
+      const messageSend: Envelope = {
+        v: PROTOCOL_VERSION,
+        type: "ask",
+        id: id,
+        data: { question: message },
       };
       wsRef.current.send(JSON.stringify(messageSend));
       setMessage("");
@@ -1,17 +1,287 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"github.com/joho/godotenv"
+	"io/fs"
 	"os"
+	"strings"
+	"time"
 )
 
+// Storage backends selectable with STORAGE_BACKEND.
+const (
+	STORAGE_MONGO  = "mongo"
+	STORAGE_MEMORY = "memory"
+)
+
+// Config is loaded once at startup by NewConfig and passed to whatever
+// needs it. Values come from, in increasing precedence: the defaults below,
+// the optional file named by CONFIG_FILE, .env and the environment.
 type Config struct {
-   LLamaUrl string `json:"llama_url"`	
+	Server    ServerConfig    `json:"server" yaml:"server"`
+	LLM       LLMConfig       `json:"llm" yaml:"llm"`
+	Embedding EmbeddingConfig `json:"embedding" yaml:"embedding"`
+	Qdrant    QdrantConfig    `json:"qdrant" yaml:"qdrant"`
+	Auth      AuthConfig      `json:"-" yaml:"auth"`
+	Websocket WebsocketConfig `json:"websocket" yaml:"websocket"`
+	Scheduler SchedulerConfig `json:"scheduler" yaml:"scheduler"`
+	Mongo     MongoConfig     `json:"mongo" yaml:"mongo"`
+	// Storage is STORAGE_MONGO, or STORAGE_MEMORY to keep all data in the
+	// process for tests and local development.
+	Storage string `json:"storage" yaml:"storage"`
+	// AdminUsernames receive admin tokens on login.
+	AdminUsernames []string `json:"admin_usernames" yaml:"admin_usernames"`
+}
+
+type ServerConfig struct {
+	Port string `json:"port" yaml:"port"`
+	// ShutdownTimeout bounds how long in-flight requests and answers may
+	// run after the server is asked to stop.
+	ShutdownTimeout time.Duration `json:"shutdown_timeout" yaml:"shutdown_timeout"`
+	// HealthCheckTimeout bounds each dependency check made by /readyz.
+	HealthCheckTimeout time.Duration `json:"health_check_timeout" yaml:"health_check_timeout"`
+}
+
+// LLMConfig points at an Ollama compatible generate endpoint.
+type LLMConfig struct {
+	URL        string `json:"url" yaml:"url"`
+	Model      string `json:"model" yaml:"model"`
+	JudgeModel string `json:"judge_model" yaml:"judge_model"`
+}
+
+type EmbeddingConfig struct {
+	URL   string `json:"url" yaml:"url"`
+	Model string `json:"model" yaml:"model"`
+}
+
+type QdrantConfig struct {
+	Host       string `json:"host" yaml:"host"`
+	Port       int64  `json:"port" yaml:"port"`
+	Collection string `json:"collection" yaml:"collection"`
+}
+
+// AuthConfig signs and expires access tokens.
+type AuthConfig struct {
+	SecretKey string        `json:"-" yaml:"secret_key"`
+	TokenTTL  time.Duration `json:"token_ttl" yaml:"token_ttl"`
+}
+
+type WebsocketConfig struct {
+	PingInterval   time.Duration `json:"ping_interval" yaml:"ping_interval"`
+	PongWait       time.Duration `json:"pong_wait" yaml:"pong_wait"`
+	WriteWait      time.Duration `json:"write_wait" yaml:"write_wait"`
+	IdleTimeout    time.Duration `json:"idle_timeout" yaml:"idle_timeout"`
+	MaxMessageSize int64         `json:"max_message_size" yaml:"max_message_size"`
+}
+
+// SchedulerConfig holds the cron spec of every scheduled job. An empty spec,
+// or "off", disables the job.
+type SchedulerConfig struct {
+	Timezone              string         `json:"timezone" yaml:"timezone"`
+	Location              *time.Location `json:"-" yaml:"-"`
+	VerseOfTheDaySchedule string         `json:"verse_of_the_day_schedule" yaml:"verse_of_the_day_schedule"`
 }
 
+// MongoConfig selects the database every repository works in and bounds
+// each repository operation, the connection pool and the startup ping.
+type MongoConfig struct {
+	URI              string        `json:"-" yaml:"uri"`
+	Database         string        `json:"database" yaml:"database"`
+	OperationTimeout time.Duration `json:"operation_timeout" yaml:"operation_timeout"`
+	MaxPoolSize      int64         `json:"max_pool_size" yaml:"max_pool_size"`
+	MinPoolSize      int64         `json:"min_pool_size" yaml:"min_pool_size"`
+	MaxConnIdleTime  time.Duration `json:"max_conn_idle_time" yaml:"max_conn_idle_time"`
+	ConnectTimeout   time.Duration `json:"connect_timeout" yaml:"connect_timeout"`
+	// ConnectRetries is how many times the startup ping is attempted,
+	// waiting RetryBackoff after the first failure and twice as long after
+	// each further one.
+	ConnectRetries int64         `json:"connect_retries" yaml:"connect_retries"`
+	RetryBackoff   time.Duration `json:"retry_backoff" yaml:"retry_backoff"`
+	// MigrateOnStartup applies pending migrations before the server starts.
+	MigrateOnStartup bool `json:"migrate_on_startup" yaml:"migrate_on_startup"`
+}
+
+// ValidationError lists every problem found while loading the
+// configuration, so they can all be fixed in one go.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+func defaultConfig() Config {
+	return Config{
+		Server: ServerConfig{Port: "8080", ShutdownTimeout: 30 * time.Second, HealthCheckTimeout: 2 * time.Second},
+		LLM:    LLMConfig{Model: "llama3.1"},
+		Embedding: EmbeddingConfig{
+			URL:   "http://localhost:11434/api/embeddings",
+			Model: "mxbai-embed-large",
+		},
+		Qdrant: QdrantConfig{Host: "localhost", Port: 6334, Collection: "test_collection"},
+		Auth:   AuthConfig{TokenTTL: 24 * time.Hour},
+		Websocket: WebsocketConfig{
+			PingInterval:   30 * time.Second,
+			PongWait:       60 * time.Second,
+			WriteWait:      10 * time.Second,
+			IdleTimeout:    10 * time.Minute,
+			MaxMessageSize: 64 * 1024,
+		},
+		Scheduler: SchedulerConfig{Timezone: "UTC", VerseOfTheDaySchedule: "@daily"},
+		Mongo: MongoConfig{
+			Database:         "bhagabad_gita",
+			OperationTimeout: 5 * time.Second,
+			MaxPoolSize:      100,
+			MaxConnIdleTime:  5 * time.Minute,
+			ConnectTimeout:   10 * time.Second,
+			ConnectRetries:   5,
+			RetryBackoff:     time.Second,
+			MigrateOnStartup: true,
+		},
+		Storage: STORAGE_MONGO,
+	}
+}
+
+// NewConfig loads the configuration the server needs and fails with a
+// *ValidationError naming every missing or invalid setting.
 func NewConfig() (*Config, error) {
-	llamaUrl := os.Getenv("LLAMA_URL")
-	config := &Config{
-		LLamaUrl: llamaUrl,
-	} 
-	return config, nil
+	conf, problems := load()
+	if conf != nil {
+		problems = append(problems, conf.required()...)
+	}
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	return conf, nil
+}
+
+// Load is NewConfig for tools that only use part of the configuration: it
+// reports invalid values but not missing ones.
+func Load() (*Config, error) {
+	conf, problems := load()
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	return conf, nil
+}
+
+func load() (*Config, []string) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, []string{fmt.Sprintf(".env: %v", err)}
+	}
+	conf := defaultConfig()
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := loadFile(path, &conf); err != nil {
+			return nil, []string{err.Error()}
+		}
+	}
+	env := &envLoader{}
+	env.string("PORT", &conf.Server.Port)
+	env.duration("SHUTDOWN_TIMEOUT", &conf.Server.ShutdownTimeout)
+	env.duration("HEALTH_CHECK_TIMEOUT", &conf.Server.HealthCheckTimeout)
+	env.string("LLAMA_URL", &conf.LLM.URL)
+	env.string("LLM_MODEL", &conf.LLM.Model)
+	env.string("JUDGE_MODEL", &conf.LLM.JudgeModel)
+	env.string("EMBEDDING_URL", &conf.Embedding.URL)
+	env.string("EMBEDDING_MODEL", &conf.Embedding.Model)
+	env.string("QDRANT_HOST", &conf.Qdrant.Host)
+	env.number("QDRANT_PORT", &conf.Qdrant.Port)
+	env.string("QDRANT_COLLECTION", &conf.Qdrant.Collection)
+	env.string("SECRET_KEY", &conf.Auth.SecretKey)
+	env.duration("TOKEN_TTL", &conf.Auth.TokenTTL)
+	env.duration("WS_PING_INTERVAL", &conf.Websocket.PingInterval)
+	env.duration("WS_PONG_WAIT", &conf.Websocket.PongWait)
+	env.duration("WS_WRITE_WAIT", &conf.Websocket.WriteWait)
+	env.duration("WS_IDLE_TIMEOUT", &conf.Websocket.IdleTimeout)
+	env.number("WS_MAX_MESSAGE_SIZE", &conf.Websocket.MaxMessageSize)
+	env.string("SCHEDULER_TIMEZONE", &conf.Scheduler.Timezone)
+	env.string("VERSE_OF_THE_DAY_SCHEDULE", &conf.Scheduler.VerseOfTheDaySchedule)
+	env.string("MONGODB_URI", &conf.Mongo.URI)
+	env.string("MONGODB_DATABASE", &conf.Mongo.Database)
+	env.duration("MONGODB_OPERATION_TIMEOUT", &conf.Mongo.OperationTimeout)
+	env.number("MONGODB_MAX_POOL_SIZE", &conf.Mongo.MaxPoolSize)
+	env.number("MONGODB_MIN_POOL_SIZE", &conf.Mongo.MinPoolSize)
+	env.duration("MONGODB_MAX_CONN_IDLE_TIME", &conf.Mongo.MaxConnIdleTime)
+	env.duration("MONGODB_CONNECT_TIMEOUT", &conf.Mongo.ConnectTimeout)
+	env.number("MONGODB_CONNECT_RETRIES", &conf.Mongo.ConnectRetries)
+	env.duration("MONGODB_RETRY_BACKOFF", &conf.Mongo.RetryBackoff)
+	env.boolean("MIGRATE_ON_STARTUP", &conf.Mongo.MigrateOnStartup)
+	env.string("STORAGE_BACKEND", &conf.Storage)
+	env.list("ADMIN_USERNAMES", &conf.AdminUsernames)
+	problems := append(env.problems, conf.validate()...)
+	return &conf, problems
+}
+
+// validate checks the loaded values and resolves the derived ones.
+func (c *Config) validate() []string {
+	var problems []string
+	durations := []struct {
+		key   string
+		value time.Duration
+	}{
+		{"SHUTDOWN_TIMEOUT", c.Server.ShutdownTimeout},
+		{"HEALTH_CHECK_TIMEOUT", c.Server.HealthCheckTimeout},
+		{"TOKEN_TTL", c.Auth.TokenTTL},
+		{"WS_PING_INTERVAL", c.Websocket.PingInterval},
+		{"WS_PONG_WAIT", c.Websocket.PongWait},
+		{"WS_WRITE_WAIT", c.Websocket.WriteWait},
+		{"WS_IDLE_TIMEOUT", c.Websocket.IdleTimeout},
+		{"MONGODB_OPERATION_TIMEOUT", c.Mongo.OperationTimeout},
+		{"MONGODB_MAX_CONN_IDLE_TIME", c.Mongo.MaxConnIdleTime},
+		{"MONGODB_CONNECT_TIMEOUT", c.Mongo.ConnectTimeout},
+		{"MONGODB_RETRY_BACKOFF", c.Mongo.RetryBackoff},
+	}
+	for _, duration := range durations {
+		if duration.value <= 0 {
+			problems = append(problems, fmt.Sprintf("%s must be a positive duration", duration.key))
+		}
+	}
+	if c.Qdrant.Port <= 0 || c.Qdrant.Port > 65535 {
+		problems = append(problems, fmt.Sprintf("QDRANT_PORT %d is not a valid port", c.Qdrant.Port))
+	}
+	if c.Websocket.MaxMessageSize <= 0 {
+		problems = append(problems, "WS_MAX_MESSAGE_SIZE must be positive")
+	}
+	if c.Websocket.PingInterval >= c.Websocket.PongWait {
+		problems = append(problems, fmt.Sprintf("WS_PING_INTERVAL (%s) must be shorter than WS_PONG_WAIT (%s)", c.Websocket.PingInterval, c.Websocket.PongWait))
+	}
+	if c.Mongo.MaxPoolSize <= 0 || c.Mongo.MinPoolSize < 0 || c.Mongo.MinPoolSize > c.Mongo.MaxPoolSize {
+		problems = append(problems, fmt.Sprintf("MONGODB_MIN_POOL_SIZE (%d) and MONGODB_MAX_POOL_SIZE (%d) must satisfy 0 <= min <= max and max > 0", c.Mongo.MinPoolSize, c.Mongo.MaxPoolSize))
+	}
+	if c.Mongo.ConnectRetries <= 0 {
+		problems = append(problems, "MONGODB_CONNECT_RETRIES must be positive")
+	}
+	if c.Storage != STORAGE_MONGO && c.Storage != STORAGE_MEMORY {
+		problems = append(problems, fmt.Sprintf("STORAGE_BACKEND %q must be %q or %q", c.Storage, STORAGE_MONGO, STORAGE_MEMORY))
+	}
+	location, err := time.LoadLocation(c.Scheduler.Timezone)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("SCHEDULER_TIMEZONE %q: %v", c.Scheduler.Timezone, err))
+	}
+	c.Scheduler.Location = location
+	if c.Scheduler.VerseOfTheDaySchedule == "off" {
+		c.Scheduler.VerseOfTheDaySchedule = ""
+	}
+	if c.LLM.JudgeModel == "" {
+		c.LLM.JudgeModel = c.LLM.Model
+	}
+	return problems
+}
+
+// required lists the settings the server cannot start without.
+func (c *Config) required() []string {
+	var missing []string
+	if c.LLM.URL == "" {
+		missing = append(missing, "LLAMA_URL is required")
+	}
+	if c.Auth.SecretKey == "" {
+		missing = append(missing, "SECRET_KEY is required")
+	}
+	if c.Storage == STORAGE_MONGO && c.Mongo.URI == "" {
+		missing = append(missing, "MONGODB_URI is required when STORAGE_BACKEND is mongo")
+	}
+	return missing
 }
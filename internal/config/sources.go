@@ -0,0 +1,111 @@
+package config
+
+import (
+	"fmt"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// loadFile overlays a YAML or TOML file onto conf. Keys follow the yaml
+// tags, e.g.
+//
+//	mongo:
+//	  database: bhagabad_gita
+//	  operation_timeout: 5s
+func loadFile(path string, conf *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("CONFIG_FILE: %w", err)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+	case ".toml":
+		// TOML is decoded generically and re-encoded as YAML so both
+		// formats share the yaml tags and duration parsing.
+		var values map[string]interface{}
+		if err := toml.Unmarshal(data, &values); err != nil {
+			return fmt.Errorf("CONFIG_FILE %s: %w", path, err)
+		}
+		if data, err = yaml.Marshal(values); err != nil {
+			return fmt.Errorf("CONFIG_FILE %s: %w", path, err)
+		}
+	default:
+		return fmt.Errorf("CONFIG_FILE %s: expected a .yaml, .yml or .toml file", path)
+	}
+	if err := yaml.Unmarshal(data, conf); err != nil {
+		return fmt.Errorf("CONFIG_FILE %s: %w", path, err)
+	}
+	return nil
+}
+
+// envLoader overrides settings with the environment variables that are set
+// and collects the ones that cannot be parsed.
+type envLoader struct {
+	problems []string
+}
+
+func (l *envLoader) string(key string, target *string) {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		*target = value
+	}
+}
+
+func (l *envLoader) duration(key string, target *time.Duration) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		l.problems = append(l.problems, fmt.Sprintf("%s: invalid duration %q", key, value))
+		return
+	}
+	*target = duration
+}
+
+func (l *envLoader) number(key string, target *int64) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return
+	}
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		l.problems = append(l.problems, fmt.Sprintf("%s: invalid number %q", key, value))
+		return
+	}
+	*target = number
+}
+
+// boolean accepts the values strconv.ParseBool does, such as true, false,
+// 1 and 0.
+func (l *envLoader) boolean(key string, target *bool) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return
+	}
+	boolean, err := strconv.ParseBool(value)
+	if err != nil {
+		l.problems = append(l.problems, fmt.Sprintf("%s: invalid boolean %q", key, value))
+		return
+	}
+	*target = boolean
+}
+
+func (l *envLoader) list(key string, target *[]string) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return
+	}
+	var values []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	*target = values
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEnvLoader(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		load    func(l *envLoader) interface{}
+		want    interface{}
+		problem string
+	}{
+		{name: "boolean true", value: "true", load: loadBoolean(false), want: true},
+		{name: "boolean 0", value: "0", load: loadBoolean(true), want: false},
+		{name: "boolean FALSE", value: "FALSE", load: loadBoolean(true), want: false},
+		{name: "boolean invalid", value: "yes", load: loadBoolean(true), want: true, problem: `TEST_VALUE: invalid boolean "yes"`},
+		{name: "boolean unset", value: "", load: loadBoolean(true), want: true},
+		{name: "duration", value: "90s", load: loadDuration(time.Second), want: 90 * time.Second},
+		{name: "duration without unit", value: "90", load: loadDuration(time.Second), want: time.Second, problem: `TEST_VALUE: invalid duration "90"`},
+		{name: "number", value: "12", load: loadNumber(1), want: int64(12)},
+		{name: "number invalid", value: "12x", load: loadNumber(1), want: int64(1), problem: `TEST_VALUE: invalid number "12x"`},
+		{name: "list", value: " krishna, ,arjuna ", load: loadList(nil), want: []string{"krishna", "arjuna"}},
+		{name: "string", value: "llama3.1", load: loadString("default"), want: "llama3.1"},
+		{name: "string unset", value: "", load: loadString("default"), want: "default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_VALUE", tt.value)
+			loader := &envLoader{}
+			if got := tt.load(loader); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("value = %#v, want %#v", got, tt.want)
+			}
+			var want []string
+			if tt.problem != "" {
+				want = []string{tt.problem}
+			}
+			if !reflect.DeepEqual(loader.problems, want) {
+				t.Errorf("problems = %q, want %q", loader.problems, want)
+			}
+		})
+	}
+}
+
+func loadBoolean(initial bool) func(l *envLoader) interface{} {
+	return func(l *envLoader) interface{} {
+		l.boolean("TEST_VALUE", &initial)
+		return initial
+	}
+}
+
+func loadDuration(initial time.Duration) func(l *envLoader) interface{} {
+	return func(l *envLoader) interface{} {
+		l.duration("TEST_VALUE", &initial)
+		return initial
+	}
+}
+
+func loadNumber(initial int64) func(l *envLoader) interface{} {
+	return func(l *envLoader) interface{} {
+		l.number("TEST_VALUE", &initial)
+		return initial
+	}
+}
+
+func loadList(initial []string) func(l *envLoader) interface{} {
+	return func(l *envLoader) interface{} {
+		l.list("TEST_VALUE", &initial)
+		return initial
+	}
+}
+
+func loadString(initial string) func(l *envLoader) interface{} {
+	return func(l *envLoader) interface{} {
+		l.string("TEST_VALUE", &initial)
+		return initial
+	}
+}
+
+// setenv sets the variables for the test, clearing the ones load reads in
+// these tests so that the environment running them does not leak in.
+func setenv(t *testing.T, values map[string]string) {
+	t.Helper()
+	for _, key := range []string{"CONFIG_FILE", "PORT", "MONGODB_DATABASE", "MONGODB_OPERATION_TIMEOUT", "MONGODB_MAX_POOL_SIZE", "MIGRATE_ON_STARTUP", "TOKEN_TTL", "QDRANT_PORT", "STORAGE_BACKEND", "WS_PING_INTERVAL"} {
+		t.Setenv(key, "")
+	}
+	for key, value := range values {
+		t.Setenv(key, value)
+	}
+}
+
+func writeFile(t *testing.T, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	files := map[string]string{
+		"config.yaml": `
+server:
+  port: "9000"
+mongo:
+  database: file_db
+  operation_timeout: 7s
+  migrate_on_startup: false
+`,
+		"config.toml": `
+[server]
+port = "9000"
+
+[mongo]
+database = "file_db"
+operation_timeout = "7s"
+migrate_on_startup = false
+`,
+	}
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			setenv(t, map[string]string{
+				"CONFIG_FILE":        writeFile(t, name, content),
+				"MONGODB_DATABASE":   "env_db",
+				"MIGRATE_ON_STARTUP": "true",
+			})
+			conf, err := Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			checks := []struct {
+				setting   string
+				got, want interface{}
+			}{
+				{"port from the file", conf.Server.Port, "9000"},
+				{"database from the environment", conf.Mongo.Database, "env_db"},
+				{"operation timeout from the file", conf.Mongo.OperationTimeout, 7 * time.Second},
+				{"migrate on startup from the environment", conf.Mongo.MigrateOnStartup, true},
+				{"pool size default", conf.Mongo.MaxPoolSize, int64(100)},
+			}
+			for _, check := range checks {
+				if !reflect.DeepEqual(check.got, check.want) {
+					t.Errorf("%s = %v, want %v", check.setting, check.got, check.want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadReportsEveryInvalidValue(t *testing.T) {
+	setenv(t, map[string]string{
+		"MIGRATE_ON_STARTUP": "yes",
+		"TOKEN_TTL":          "1day",
+		"QDRANT_PORT":        "abc",
+		"STORAGE_BACKEND":    "disk",
+		"WS_PING_INTERVAL":   "2m",
+	})
+	_, err := Load()
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("Load() error = %v, want a *ValidationError", err)
+	}
+	for _, want := range []string{
+		`MIGRATE_ON_STARTUP: invalid boolean "yes"`,
+		`TOKEN_TTL: invalid duration "1day"`,
+		`QDRANT_PORT: invalid number "abc"`,
+		`STORAGE_BACKEND "disk"`,
+		"WS_PING_INTERVAL (2m0s) must be shorter than WS_PONG_WAIT (1m0s)",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Load() error does not mention %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestLoadRejectsUnknownFileFormat(t *testing.T) {
+	setenv(t, map[string]string{"CONFIG_FILE": writeFile(t, "config.json", "{}")})
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "expected a .yaml, .yml or .toml file") {
+		t.Errorf("Load() error = %v, want an unsupported format error", err)
+	}
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// BookmarkTarget points at a verse or at a chunk id returned with a chat
+// answer's context.
+type BookmarkTarget struct {
+	Chapter int    `json:"chapter,omitempty" bson:"chapter,omitempty"`
+	Verse   int    `json:"verse,omitempty" bson:"verse,omitempty"`
+	ChunkId string `json:"chunkId,omitempty" bson:"chunkId,omitempty"`
+}
+
+func (t BookmarkTarget) Validate() error {
+	if t.ChunkId != "" {
+		return nil
+	}
+	if t.Chapter <= 0 || t.Chapter > CHAPTER_COUNT || t.Verse <= 0 {
+		return fmt.Errorf("target needs a chapter (1-%d) and verse, or a chunkId", CHAPTER_COUNT)
+	}
+	return nil
+}
+
+// Bookmark is a saved passage with an optional personal note and tags.
+type Bookmark struct {
+	Id        string         `json:"id" bson:"id"`
+	Username  string         `json:"-" bson:"username"`
+	Target    BookmarkTarget `json:"target" bson:"target"`
+	Title     string         `json:"title,omitempty" bson:"title,omitempty"`
+	Note      string         `json:"note,omitempty" bson:"note,omitempty"`
+	Tags      []string       `json:"tags" bson:"tags"`
+	CreatedAt time.Time      `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt" bson:"updatedAt"`
+}
+
+// BookmarkInput is the body accepted when creating or updating a bookmark.
+// Nil fields are left unchanged on update.
+type BookmarkInput struct {
+	Target *BookmarkTarget `json:"target"`
+	Title  *string         `json:"title"`
+	Note   *string         `json:"note"`
+	Tags   []string        `json:"tags"`
+}
+
+type BookmarkQuery struct {
+	Tag    string `form:"tag"`
+	Search string `form:"q"`
+	PageQuery
+}
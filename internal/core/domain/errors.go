@@ -0,0 +1,12 @@
+package domain
+
+import "errors"
+
+var ErrNotFound = errors.New("not found")
+
+// ErrDuplicate is returned when a write violates a unique index.
+var ErrDuplicate = errors.New("duplicate")
+
+// ErrInvalidCursor is returned for a page cursor that is malformed or was
+// issued for a different sort order.
+var ErrInvalidCursor = errors.New("invalid cursor")
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	RatingUp   = "up"
+	RatingDown = "down"
+)
+
+// FeedbackInput is sent by clients over REST or as a websocket feedback frame.
+type FeedbackInput struct {
+	MessageId string `json:"messageId"`
+	Rating    string `json:"rating"`
+	Reason    string `json:"reason,omitempty"`
+	Comment   string `json:"comment,omitempty"`
+}
+
+func (f FeedbackInput) Validate() error {
+	if f.MessageId == "" {
+		return &ProtocolError{Code: ErrorCodeInvalidMessage, Message: "messageId is required"}
+	}
+	if f.Rating != RatingUp && f.Rating != RatingDown {
+		return &ProtocolError{Code: ErrorCodeInvalidMessage, Message: fmt.Sprintf("rating must be %q or %q", RatingUp, RatingDown)}
+	}
+	if len(f.Comment) > 2000 {
+		return &ProtocolError{Code: ErrorCodeInvalidMessage, Message: "comment must be at most 2000 characters"}
+	}
+	return nil
+}
+
+// Feedback is stored with a copy of the answer's prompt, context and model
+// so reports don't need to join against the answers collection.
+type Feedback struct {
+	Id             string    `json:"id" bson:"id"`
+	MessageId      string    `json:"messageId" bson:"messageId"`
+	Username       string    `json:"username,omitempty" bson:"username,omitempty"`
+	Rating         string    `json:"rating" bson:"rating"`
+	Reason         string    `json:"reason,omitempty" bson:"reason,omitempty"`
+	Comment        string    `json:"comment,omitempty" bson:"comment,omitempty"`
+	Question       string    `json:"question" bson:"question"`
+	Prompt         string    `json:"prompt" bson:"prompt"`
+	PromptTemplate string    `json:"promptTemplate" bson:"promptTemplate"`
+	Model          string    `json:"model" bson:"model"`
+	ContextIds     []string  `json:"contextIds" bson:"contextIds"`
+	CreatedAt      time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+type FeedbackGroup struct {
+	PromptTemplate string         `json:"promptTemplate"`
+	Model          string         `json:"model"`
+	Total          int            `json:"total"`
+	Up             int            `json:"up"`
+	Down           int            `json:"down"`
+	Satisfaction   float64        `json:"satisfaction"`
+	Reasons        map[string]int `json:"reasons"`
+}
+
+type FeedbackReport struct {
+	Total  int             `json:"total"`
+	Groups []FeedbackGroup `json:"groups"`
+}
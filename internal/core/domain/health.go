@@ -0,0 +1,22 @@
+package domain
+
+const (
+	HealthStatusOK   = "ok"
+	HealthStatusDown = "down"
+)
+
+// DependencyHealth is the outcome of checking one dependency. Details holds
+// what the check learned, such as the collection's vector size.
+type DependencyHealth struct {
+	Name      string                 `json:"name"`
+	Status    string                 `json:"status"`
+	LatencyMs float64                `json:"latencyMs"`
+	Error     string                 `json:"error,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// HealthReport is ok only when every dependency is.
+type HealthReport struct {
+	Status       string             `json:"status"`
+	Dependencies []DependencyHealth `json:"dependencies,omitempty"`
+}
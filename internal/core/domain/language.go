@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Languages questions can be asked and answered in. Sanskrit answers are
+// written in IAST transliteration rather than Devanagari.
+const (
+	LanguageEnglish  = "en"
+	LanguageHindi    = "hi"
+	LanguageBengali  = "bn"
+	LanguageSanskrit = "sa"
+)
+
+// LANGUAGE_NAMES maps each supported language code to the name used when
+// instructing the model.
+var LANGUAGE_NAMES = map[string]string{
+	LanguageEnglish:  "English",
+	LanguageHindi:    "Hindi",
+	LanguageBengali:  "Bengali",
+	LanguageSanskrit: "Sanskrit in IAST transliteration",
+}
+
+// IsValidLanguage reports whether lang is empty (detect from the question)
+// or one of the supported language codes.
+func IsValidLanguage(lang string) bool {
+	if lang == "" {
+		return true
+	}
+	_, ok := LANGUAGE_NAMES[lang]
+	return ok
+}
+
+// DetectLanguage guesses the language of text from its script: Bengali or
+// Devanagari letters, or the IAST diacritics used to transliterate Sanskrit.
+// Anything else is treated as English.
+func DetectLanguage(text string) string {
+	var bengali, devanagari, latin int
+	iast := false
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Bengali, r):
+			bengali++
+		case unicode.Is(unicode.Devanagari, r):
+			devanagari++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+			if strings.ContainsRune("āīūṛṝḷṅñṭḍṇśṣṃḥĀĪŪṚṜḶṄÑṬḌṆŚṢṂḤ", r) {
+				iast = true
+			}
+		}
+	}
+	switch {
+	case bengali > 0 && bengali >= devanagari && bengali >= latin:
+		return LanguageBengali
+	case devanagari > 0 && devanagari >= latin:
+		return LanguageHindi
+	case iast:
+		return LanguageSanskrit
+	}
+	return LanguageEnglish
+}
@@ -1,20 +1,79 @@
 package domain
 
+import "time"
+
 type Query struct {
 	Search string `json:"search" bson:"search"`
 }
 
+// ChatResponse is one line of Ollama's streaming /api/generate output. The
+// statistics are only present on the final frame, where Done is true.
 type ChatResponse struct {
-	Response string `json:"response"`
+	Response           string `json:"response"`
+	Done               bool   `json:"done"`
+	PromptEvalCount    int    `json:"prompt_eval_count"`
+	EvalCount          int    `json:"eval_count"`
+	TotalDuration      int64  `json:"total_duration"`
+	LoadDuration       int64  `json:"load_duration"`
+	PromptEvalDuration int64  `json:"prompt_eval_duration"`
+	EvalDuration       int64  `json:"eval_duration"`
+	// Error is set instead of the fields above when Ollama fails mid-stream.
+	Error string `json:"error,omitempty"`
 }
 
-type WebsocketMessage struct {
-	ClientId  string `json:"clientId"`
-	MessageId int    `json:"messageId"`
-	Payload   string `json:"payload"`
-	MsgType   string `json:"msgType"`
-}
 type VectorSearchResult struct {
-	PageNum uint64 `json:"rank"`
-	Content string `json:"content"`
-}
\ No newline at end of file
+	Id      string  `json:"id,omitempty"`
+	PageNum uint64  `json:"pageNum"`
+	PageIdx uint64  `json:"pageIdx,omitempty"`
+	Chapter uint64  `json:"chapter,omitempty"`
+	Verse   uint64  `json:"verse,omitempty"`
+	Content string  `json:"content"`
+	Score   float32 `json:"score"`
+}
+
+type SearchOptions struct {
+	Limit   uint64
+	Chapter uint64
+}
+
+// SearchHit is a ranked passage returned by the search API, with the query
+// terms found in it wrapped in <mark> tags.
+type SearchHit struct {
+	Rank int `json:"rank"`
+	VectorSearchResult
+	Highlighted  string   `json:"highlighted"`
+	MatchedTerms []string `json:"matchedTerms"`
+}
+
+// Answer is the non-streaming result of the chat pipeline.
+type Answer struct {
+	MessageId string               `json:"messageId"`
+	Question  string               `json:"question"`
+	Lang      string               `json:"lang"`
+	Answer    string               `json:"answer"`
+	Sources   []VectorSearchResult `json:"sources"`
+	Timings   AnswerTimings        `json:"timings"`
+	Usage     DoneData             `json:"usage"`
+}
+
+type AnswerTimings struct {
+	RetrievalMs  float64 `json:"retrievalMs"`
+	GenerationMs float64 `json:"generationMs"`
+	TotalMs      float64 `json:"totalMs"`
+}
+
+// AnswerRecord is stored for every completed answer so that feedback can be
+// tied back to the prompt, retrieved context and model that produced it.
+type AnswerRecord struct {
+	MessageId      string    `json:"messageId" bson:"messageId"`
+	Username       string    `json:"-" bson:"username,omitempty"`
+	Question       string    `json:"question" bson:"question"`
+	Lang           string    `json:"lang" bson:"lang"`
+	RetrievalQuery string    `json:"retrievalQuery" bson:"retrievalQuery"`
+	Prompt         string    `json:"prompt" bson:"prompt"`
+	PromptTemplate string    `json:"promptTemplate" bson:"promptTemplate"`
+	Model          string    `json:"model" bson:"model"`
+	ContextIds     []string  `json:"contextIds" bson:"contextIds"`
+	Answer         string    `json:"answer" bson:"answer"`
+	CreatedAt      time.Time `json:"createdAt" bson:"createdAt"`
+}
@@ -0,0 +1,24 @@
+package domain
+
+const (
+	DEFAULT_PAGE_LIMIT = 20
+	MAX_PAGE_LIMIT     = 100
+)
+
+// Page is one page of a list. NextCursor is empty on the last page and is
+// otherwise passed back as the cursor query parameter to fetch the next one.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// PageQuery is the pagination accepted by list endpoints, e.g.
+// ?limit=20&sort=-createdAt&fields=title,tags&cursor=...
+// Sort names one field, prefixed with "-" for descending order, and fields
+// is a comma separated projection.
+type PageQuery struct {
+	Cursor string `form:"cursor"`
+	Limit  int64  `form:"limit"`
+	Sort   string `form:"sort"`
+	Fields string `form:"fields"`
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+const MAX_DISPLAY_NAME_LENGTH = 64
+
+const MAX_TRANSLATION_LENGTH = 64
+
+// Preferences are applied to the signed-in user's asks whenever the ask
+// leaves the matching field empty.
+type Preferences struct {
+	Language     string `json:"language,omitempty" bson:"preferredLanguage,omitempty"`
+	Translation  string `json:"translation,omitempty" bson:"preferredTranslation,omitempty"`
+	AnswerLength string `json:"answerLength,omitempty" bson:"answerLength,omitempty"`
+	Granularity  string `json:"granularity,omitempty" bson:"granularity,omitempty"`
+}
+
+func (p Preferences) Validate() error {
+	if !IsValidLanguage(p.Language) {
+		return fmt.Errorf("unsupported language %q", p.Language)
+	}
+	if len(p.Translation) > MAX_TRANSLATION_LENGTH {
+		return fmt.Errorf("translation must be at most %d characters", MAX_TRANSLATION_LENGTH)
+	}
+	if !IsValidAnswerLength(p.AnswerLength) {
+		return fmt.Errorf("unsupported answer length %q", p.AnswerLength)
+	}
+	if !IsValidGranularity(p.Granularity) {
+		return fmt.Errorf("unsupported granularity %q", p.Granularity)
+	}
+	return nil
+}
+
+// Apply fills the fields the ask leaves empty from the preferences.
+func (p Preferences) Apply(ask AskData) AskData {
+	if ask.Lang == "" {
+		ask.Lang = p.Language
+	}
+	if ask.Translation == "" {
+		ask.Translation = p.Translation
+	}
+	if ask.Length == "" {
+		ask.Length = p.AnswerLength
+	}
+	if ask.Granularity == "" {
+		ask.Granularity = p.Granularity
+	}
+	return ask
+}
+
+// Profile is the part of a user returned by /v1/me.
+type Profile struct {
+	Username    string      `json:"username"`
+	Email       string      `json:"email"`
+	DisplayName string      `json:"displayName"`
+	Preferences Preferences `json:"preferences"`
+}
+
+func NewProfile(user User) Profile {
+	return Profile{
+		Username:    user.Username,
+		Email:       user.Email,
+		DisplayName: user.DisplayName,
+		Preferences: user.Preferences,
+	}
+}
+
+// ProfileInput is the body accepted by PATCH /v1/me. Nil fields are left
+// unchanged and empty strings clear a preference.
+type ProfileInput struct {
+	DisplayName  *string `json:"displayName"`
+	Language     *string `json:"language"`
+	Translation  *string `json:"translation"`
+	AnswerLength *string `json:"answerLength"`
+	Granularity  *string `json:"granularity"`
+}
+
+// ApplyTo returns user with the input's fields applied, or an error when
+// the result is invalid.
+func (in ProfileInput) ApplyTo(user User) (User, error) {
+	if in.DisplayName != nil {
+		user.DisplayName = strings.TrimSpace(*in.DisplayName)
+	}
+	if in.Language != nil {
+		user.Preferences.Language = *in.Language
+	}
+	if in.Translation != nil {
+		user.Preferences.Translation = strings.TrimSpace(*in.Translation)
+	}
+	if in.AnswerLength != nil {
+		user.Preferences.AnswerLength = *in.AnswerLength
+	}
+	if in.Granularity != nil {
+		user.Preferences.Granularity = *in.Granularity
+	}
+	if len(user.DisplayName) > MAX_DISPLAY_NAME_LENGTH {
+		return user, fmt.Errorf("displayName must be at most %d characters", MAX_DISPLAY_NAME_LENGTH)
+	}
+	return user, user.Preferences.Validate()
+}
+
+// Fields returns the stored fields the input sets, keyed by their bson
+// names and taken from user after ApplyTo, so that only they are written.
+func (in ProfileInput) Fields(user User) map[string]interface{} {
+	fields := make(map[string]interface{})
+	if in.DisplayName != nil {
+		fields["displayName"] = user.DisplayName
+	}
+	if in.Language != nil {
+		fields["preferredLanguage"] = user.Preferences.Language
+	}
+	if in.Translation != nil {
+		fields["preferredTranslation"] = user.Preferences.Translation
+	}
+	if in.AnswerLength != nil {
+		fields["answerLength"] = user.Preferences.AnswerLength
+	}
+	if in.Granularity != nil {
+		fields["granularity"] = user.Preferences.Granularity
+	}
+	return fields
+}
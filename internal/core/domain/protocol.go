@@ -0,0 +1,269 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// PROTOCOL_VERSION is the version of the websocket envelope described in
+// websocket.schema.json. Bump it on any incompatible change.
+const PROTOCOL_VERSION = 1
+
+const (
+	MessageTypeAsk      = "ask"
+	MessageTypeContext  = "context"
+	MessageTypeToken    = "token"
+	MessageTypeSentence = "sentence"
+	MessageTypeDone     = "done"
+	MessageTypeError    = "error"
+	MessageTypeCancel   = "cancel"
+
+	MessageTypeSubscribe    = "subscribe"
+	MessageTypeUnsubscribe  = "unsubscribe"
+	MessageTypeNotification = "notification"
+
+	MessageTypeFeedback = "feedback"
+	MessageTypeAck      = "ack"
+)
+
+// Topics clients can subscribe to for server-pushed notifications.
+const (
+	TopicVerseOfTheDay = "verse-of-the-day"
+)
+
+const (
+	GranularityToken     = "token"
+	GranularitySentence  = "sentence"
+	GranularityParagraph = "paragraph"
+)
+
+// Answer lengths an ask can request. The server default leaves the length
+// to the prompt template.
+const (
+	AnswerLengthShort  = "short"
+	AnswerLengthMedium = "medium"
+	AnswerLengthLong   = "long"
+)
+
+const (
+	ErrorCodeInvalidJSON        = "invalid_json"
+	ErrorCodeUnsupportedVersion = "unsupported_version"
+	ErrorCodeUnknownType        = "unknown_type"
+	ErrorCodeInvalidMessage     = "invalid_message"
+	ErrorCodeNotFound           = "not_found"
+	ErrorCodeRetrieval          = "retrieval_failed"
+	ErrorCodeGeneration         = "generation_failed"
+	ErrorCodeCancelled          = "cancelled"
+	ErrorCodeInternal           = "internal_error"
+	ErrorCodeUnavailable        = "unavailable"
+)
+
+// Envelope wraps every websocket frame in both directions. ReplyTo points at
+// the id of the client message a server frame belongs to.
+type Envelope struct {
+	Version int             `json:"v"`
+	Type    string          `json:"type"`
+	Id      string          `json:"id"`
+	ReplyTo string          `json:"replyTo,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+type AskData struct {
+	Question    string `json:"question" form:"question"`
+	Granularity string `json:"granularity,omitempty" form:"granularity"`
+	// Lang is the language to answer in. When empty it is detected from
+	// the question.
+	Lang string `json:"lang,omitempty" form:"lang"`
+	// Translation names the translation to quote verses from.
+	Translation string `json:"translation,omitempty" form:"translation"`
+	Length      string `json:"length,omitempty" form:"length"`
+	// Username is set by the server for authenticated asks so the answer
+	// appears in the user's history.
+	Username string `json:"-" form:"-"`
+}
+
+type ContextData struct {
+	Passages []VectorSearchResult `json:"passages"`
+}
+
+type TokenData struct {
+	Text string `json:"text"`
+}
+
+type SentenceData struct {
+	Text string `json:"text"`
+}
+
+// DoneData closes an answer. MessageId identifies the stored answer that
+// feedback refers to. Durations are in milliseconds; token counts and
+// timings are zero when the model did not report them.
+type DoneData struct {
+	MessageId          string  `json:"messageId"`
+	PromptTokens       int     `json:"promptTokens"`
+	CompletionTokens   int     `json:"completionTokens"`
+	TotalDuration      float64 `json:"totalDurationMs"`
+	LoadDuration       float64 `json:"loadDurationMs"`
+	PromptEvalDuration float64 `json:"promptEvalDurationMs"`
+	EvalDuration       float64 `json:"evalDurationMs"`
+	TokensPerSecond    float64 `json:"tokensPerSecond"`
+}
+
+func NewDoneData(response ChatResponse) DoneData {
+	done := DoneData{
+		PromptTokens:       response.PromptEvalCount,
+		CompletionTokens:   response.EvalCount,
+		TotalDuration:      nanosToMillis(response.TotalDuration),
+		LoadDuration:       nanosToMillis(response.LoadDuration),
+		PromptEvalDuration: nanosToMillis(response.PromptEvalDuration),
+		EvalDuration:       nanosToMillis(response.EvalDuration),
+	}
+	if response.EvalDuration > 0 {
+		done.TokensPerSecond = float64(response.EvalCount) / (float64(response.EvalDuration) / 1e9)
+	}
+	return done
+}
+
+func nanosToMillis(nanos int64) float64 {
+	return float64(nanos) / 1e6
+}
+
+type SubscriptionData struct {
+	Topic string `json:"topic"`
+}
+
+type NotificationData struct {
+	Topic   string      `json:"topic"`
+	Payload interface{} `json:"payload"`
+}
+
+type ErrorData struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ProtocolError is returned by validation and carries the code sent back to
+// the client in an error frame.
+type ProtocolError struct {
+	Code    string
+	Message string
+}
+
+func (e *ProtocolError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// ParseClientEnvelope decodes and validates a frame sent by a client.
+func ParseClientEnvelope(message []byte) (Envelope, error) {
+	var envelope Envelope
+	if err := json.Unmarshal(message, &envelope); err != nil {
+		return envelope, &ProtocolError{Code: ErrorCodeInvalidJSON, Message: err.Error()}
+	}
+	if envelope.Version != PROTOCOL_VERSION {
+		return envelope, &ProtocolError{Code: ErrorCodeUnsupportedVersion, Message: fmt.Sprintf("expected protocol version %d, got %d", PROTOCOL_VERSION, envelope.Version)}
+	}
+	if envelope.Id == "" {
+		return envelope, &ProtocolError{Code: ErrorCodeInvalidMessage, Message: "id is required"}
+	}
+	switch envelope.Type {
+	case MessageTypeAsk:
+		var ask AskData
+		if err := decodeData(envelope.Data, &ask); err != nil {
+			return envelope, err
+		}
+		if err := ask.Validate(); err != nil {
+			return envelope, err
+		}
+	case MessageTypeSubscribe, MessageTypeUnsubscribe:
+		var subscription SubscriptionData
+		if err := decodeData(envelope.Data, &subscription); err != nil {
+			return envelope, err
+		}
+		if subscription.Topic != TopicVerseOfTheDay {
+			return envelope, &ProtocolError{Code: ErrorCodeInvalidMessage, Message: fmt.Sprintf("unknown topic %q", subscription.Topic)}
+		}
+	case MessageTypeFeedback:
+		var feedback FeedbackInput
+		if err := decodeData(envelope.Data, &feedback); err != nil {
+			return envelope, err
+		}
+		if err := feedback.Validate(); err != nil {
+			return envelope, err
+		}
+	case MessageTypeCancel:
+		if envelope.ReplyTo == "" {
+			return envelope, &ProtocolError{Code: ErrorCodeInvalidMessage, Message: "replyTo must reference the ask to cancel"}
+		}
+	case "":
+		return envelope, &ProtocolError{Code: ErrorCodeInvalidMessage, Message: "type is required"}
+	default:
+		return envelope, &ProtocolError{Code: ErrorCodeUnknownType, Message: fmt.Sprintf("unsupported message type %q", envelope.Type)}
+	}
+	return envelope, nil
+}
+
+func (a AskData) Validate() error {
+	if a.Question == "" {
+		return &ProtocolError{Code: ErrorCodeInvalidMessage, Message: "question is required"}
+	}
+	if !IsValidGranularity(a.Granularity) {
+		return &ProtocolError{Code: ErrorCodeInvalidMessage, Message: fmt.Sprintf("unsupported granularity %q", a.Granularity)}
+	}
+	if !IsValidLanguage(a.Lang) {
+		return &ProtocolError{Code: ErrorCodeInvalidMessage, Message: fmt.Sprintf("unsupported language %q", a.Lang)}
+	}
+	if len(a.Translation) > MAX_TRANSLATION_LENGTH {
+		return &ProtocolError{Code: ErrorCodeInvalidMessage, Message: fmt.Sprintf("translation must be at most %d characters", MAX_TRANSLATION_LENGTH)}
+	}
+	if !IsValidAnswerLength(a.Length) {
+		return &ProtocolError{Code: ErrorCodeInvalidMessage, Message: fmt.Sprintf("unsupported answer length %q", a.Length)}
+	}
+	return nil
+}
+
+// IsValidAnswerLength reports whether length is empty (server default) or
+// one of the supported answer lengths.
+func IsValidAnswerLength(length string) bool {
+	switch length {
+	case "", AnswerLengthShort, AnswerLengthMedium, AnswerLengthLong:
+		return true
+	}
+	return false
+}
+
+// IsValidGranularity reports whether granularity is empty (server default)
+// or one of the supported streaming granularities.
+func IsValidGranularity(granularity string) bool {
+	switch granularity {
+	case "", GranularityToken, GranularitySentence, GranularityParagraph:
+		return true
+	}
+	return false
+}
+
+func decodeData(data json.RawMessage, v interface{}) error {
+	if len(data) == 0 {
+		return &ProtocolError{Code: ErrorCodeInvalidMessage, Message: "data is required"}
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return &ProtocolError{Code: ErrorCodeInvalidMessage, Message: fmt.Sprintf("invalid data: %v", err)}
+	}
+	return nil
+}
+
+// NewEnvelope builds a server frame with the given payload.
+func NewEnvelope(messageType string, id string, replyTo string, data interface{}) (Envelope, error) {
+	envelope := Envelope{
+		Version: PROTOCOL_VERSION,
+		Type:    messageType,
+		Id:      id,
+		ReplyTo: replyTo,
+	}
+	if data != nil {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return envelope, err
+		}
+		envelope.Data = raw
+	}
+	return envelope, nil
+}
@@ -4,6 +4,8 @@ type User struct {
 	Email    string `json:"email" bson:"email"`
 	Username string `json:"username" bson:"username"`
 	Password string `json:"password" bson:"password"`
+	DisplayName string `json:"displayName,omitempty" bson:"displayName,omitempty"`
+	Preferences Preferences `json:"preferences" bson:",inline"`
 }
 
 type UserName struct {
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CHAPTER_COUNT is the number of chapters in the Bhagavad Gita.
+const CHAPTER_COUNT = 18
+
+type Verse struct {
+	Chapter         int               `json:"chapter" bson:"chapter"`
+	Verse           int               `json:"verse" bson:"verse"`
+	Sanskrit        string            `json:"sanskrit" bson:"sanskrit"`
+	Transliteration string            `json:"transliteration" bson:"transliteration"`
+	Translations    map[string]string `json:"translations" bson:"translations"`
+	Commentary      map[string]string `json:"commentary,omitempty" bson:"commentary,omitempty"`
+}
+
+type Chapter struct {
+	Number      int    `json:"number" bson:"number"`
+	Name        string `json:"name" bson:"name"`
+	Translation string `json:"translation" bson:"translation"`
+	Summary     string `json:"summary,omitempty" bson:"summary,omitempty"`
+	VerseCount  int    `json:"verseCount" bson:"verseCount"`
+}
+
+type ChapterWithVerses struct {
+	Chapter
+	Verses []Verse `json:"verses"`
+}
+
+type VerseRef struct {
+	Chapter int `json:"chapter" bson:"chapter"`
+	Verse   int `json:"verse" bson:"verse"`
+}
+
+func (r VerseRef) String() string {
+	return fmt.Sprintf("%d.%d", r.Chapter, r.Verse)
+}
+
+func (r VerseRef) Before(other VerseRef) bool {
+	if r.Chapter != other.Chapter {
+		return r.Chapter < other.Chapter
+	}
+	return r.Verse < other.Verse
+}
+
+type VerseRange struct {
+	From VerseRef `json:"from"`
+	To   VerseRef `json:"to"`
+}
+
+// ParseVerseRef parses references such as "2.47" or "2:47".
+func ParseVerseRef(ref string) (VerseRef, error) {
+	parts := strings.FieldsFunc(strings.TrimSpace(ref), func(r rune) bool { return r == '.' || r == ':' })
+	if len(parts) != 2 {
+		return VerseRef{}, fmt.Errorf("invalid verse reference %q, expected chapter.verse", ref)
+	}
+	chapter, err := strconv.Atoi(parts[0])
+	if err != nil || chapter <= 0 {
+		return VerseRef{}, fmt.Errorf("invalid chapter in %q", ref)
+	}
+	verse, err := strconv.Atoi(parts[1])
+	if err != nil || verse <= 0 {
+		return VerseRef{}, fmt.Errorf("invalid verse in %q", ref)
+	}
+	return VerseRef{Chapter: chapter, Verse: verse}, nil
+}
+
+// ParseVerseRange parses a single reference ("2.47"), a range within a
+// chapter ("2.47-50") or a range across chapters ("2.72-3.2").
+func ParseVerseRange(ref string) (VerseRange, error) {
+	start, end, isRange := strings.Cut(ref, "-")
+	from, err := ParseVerseRef(start)
+	if err != nil {
+		return VerseRange{}, err
+	}
+	if !isRange {
+		return VerseRange{From: from, To: from}, nil
+	}
+	var to VerseRef
+	if strings.ContainsAny(end, ".:") {
+		to, err = ParseVerseRef(end)
+	} else {
+		to, err = ParseVerseRef(fmt.Sprintf("%d.%s", from.Chapter, end))
+	}
+	if err != nil {
+		return VerseRange{}, err
+	}
+	if to.Before(from) {
+		return VerseRange{}, fmt.Errorf("invalid range %q, end is before start", ref)
+	}
+	return VerseRange{From: from, To: to}, nil
+}
+
+// VerseOfTheDay records the verse picked for a calendar date (YYYY-MM-DD) in
+// the scheduler's timezone.
+type VerseOfTheDay struct {
+	Date    string `json:"date" bson:"date"`
+	Chapter int    `json:"chapter" bson:"chapter"`
+	Verse   int    `json:"verse" bson:"verse"`
+}
+
+type VerseOfTheDayWithText struct {
+	VerseOfTheDay
+	Text Verse `json:"text"`
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "testing"
+
+func TestParseVerseRange(t *testing.T) {
+	tests := []struct {
+		ref     string
+		want    VerseRange
+		wantErr bool
+	}{
+		{ref: "2.47", want: VerseRange{From: VerseRef{2, 47}, To: VerseRef{2, 47}}},
+		{ref: "2:47", want: VerseRange{From: VerseRef{2, 47}, To: VerseRef{2, 47}}},
+		{ref: " 2.47 ", want: VerseRange{From: VerseRef{2, 47}, To: VerseRef{2, 47}}},
+		{ref: "2.47-50", want: VerseRange{From: VerseRef{2, 47}, To: VerseRef{2, 50}}},
+		{ref: "2.72-3.2", want: VerseRange{From: VerseRef{2, 72}, To: VerseRef{3, 2}}},
+		{ref: "2:72-3:2", want: VerseRange{From: VerseRef{2, 72}, To: VerseRef{3, 2}}},
+		{ref: "2.47-47", want: VerseRange{From: VerseRef{2, 47}, To: VerseRef{2, 47}}},
+		{ref: "2", wantErr: true},
+		{ref: "2.47.1", wantErr: true},
+		{ref: "0.1", wantErr: true},
+		{ref: "2.0", wantErr: true},
+		{ref: "two.47", wantErr: true},
+		{ref: "2.50-47", wantErr: true},
+		{ref: "3.2-2.72", wantErr: true},
+		{ref: "2.47-", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := ParseVerseRange(tt.ref)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseVerseRange(%q) = %v, want an error", tt.ref, got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("ParseVerseRange(%q) = %v, %v, want %v", tt.ref, got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+)
+
+type BookmarkService interface {
+	Create(ctx context.Context, username string, input domain.BookmarkInput) (domain.Bookmark, error)
+	Get(ctx context.Context, username string, id string) (domain.Bookmark, error)
+	List(ctx context.Context, username string, query domain.BookmarkQuery) (domain.Page[domain.Bookmark], error)
+	Update(ctx context.Context, username string, id string, input domain.BookmarkInput) (domain.Bookmark, error)
+	Delete(ctx context.Context, username string, id string) error
+	Export(ctx context.Context, username string, format string) ([]byte, string, error)
+}
+
+type BookmarkRepository interface {
+	BaseRepository[domain.Bookmark]
+}
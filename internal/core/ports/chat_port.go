@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+)
+
+// ChatEmitter receives the events of an answer as they are produced. It
+// returns false once the consumer has gone away, which stops the pipeline.
+type ChatEmitter func(messageType string, data interface{}) bool
+
+type ChatService interface {
+	Ask(ctx context.Context, ask domain.AskData, emit ChatEmitter) error
+	Answer(ctx context.Context, ask domain.AskData) (domain.Answer, error)
+	History(ctx context.Context, username string, query domain.PageQuery) (domain.Page[domain.AnswerRecord], error)
+}
+
+type AnswerRepository interface {
+	BaseRepository[domain.AnswerRecord]
+}
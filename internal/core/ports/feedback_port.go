@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+)
+
+type FeedbackService interface {
+	Submit(ctx context.Context, username string, input domain.FeedbackInput) (domain.Feedback, error)
+	Report(ctx context.Context) (domain.FeedbackReport, error)
+}
+
+type FeedbackRepository interface {
+	BaseRepository[domain.Feedback]
+}
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+)
+
+// HealthCheck probes one dependency the server needs to answer requests.
+type HealthCheck interface {
+	Name() string
+	Check(ctx context.Context) (map[string]interface{}, error)
+}
+
+type HealthService interface {
+	Ready(ctx context.Context) domain.HealthReport
+}
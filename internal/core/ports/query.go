@@ -0,0 +1,77 @@
+package ports
+
+// Operator compares a document field with a value in a Filter.
+type Operator string
+
+const (
+	OpEq  Operator = "$eq"
+	OpNe  Operator = "$ne"
+	OpGt  Operator = "$gt"
+	OpGte Operator = "$gte"
+	OpLt  Operator = "$lt"
+	OpLte Operator = "$lte"
+	OpIn  Operator = "$in"
+)
+
+type Condition struct {
+	Field    string
+	Operator Operator
+	Value    interface{}
+}
+
+// Filter matches documents satisfying every condition. The zero Filter
+// matches all documents. Build one with Where, e.g.
+//
+//	ports.Where("username", username).And("tags", tag)
+type Filter struct {
+	Conditions []Condition
+}
+
+func Where(field string, value interface{}) Filter {
+	return Filter{}.And(field, value)
+}
+
+// And adds an equality condition.
+func (f Filter) And(field string, value interface{}) Filter {
+	return f.AndOp(field, OpEq, value)
+}
+
+// AndOp adds a condition with an explicit operator, e.g.
+// AndOp("chapter", ports.OpGte, 2).
+func (f Filter) AndOp(field string, operator Operator, value interface{}) Filter {
+	conditions := make([]Condition, len(f.Conditions), len(f.Conditions)+1)
+	copy(conditions, f.Conditions)
+	f.Conditions = append(conditions, Condition{Field: field, Operator: operator, Value: value})
+	return f
+}
+
+type SortField struct {
+	Field      string
+	Descending bool
+}
+
+func Asc(field string) SortField {
+	return SortField{Field: field}
+}
+
+func Desc(field string) SortField {
+	return SortField{Field: field, Descending: true}
+}
+
+// FindOptions orders and bounds a Find. A zero Limit returns every match.
+type FindOptions struct {
+	Sort  []SortField
+	Skip  int64
+	Limit int64
+}
+
+// PageOptions selects one page of a FindPage. Results are ordered by Sort
+// and then by document id so that every page boundary is stable. Cursor is
+// the previous page's NextCursor, and a non-empty Fields limits the fields
+// loaded into each item.
+type PageOptions struct {
+	Sort   []SortField
+	Limit  int64
+	Cursor string
+	Fields []string
+}
@@ -1,10 +1,40 @@
 package ports
 
+import (
+	"context"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+)
+
+// BaseRepository stores documents of type T. Single-document reads return
+// domain.ErrNotFound when nothing matches.
 type BaseRepository[T any] interface {
-	Create(model T, collection string) (bool, error)
-	GetAll(collection string) ([]map[string]interface{}, error)
-	GetByField(field string, field_value string, collection string) (interface{}, error)
-	GetAllByField(field string, field_value string, collection string) ([]map[string]interface{}, error)
-	InsertData(workinforamtion interface{}, collection string) (bool, error)
-	GetData(username string, collection string) (interface{}, error)
+	Create(ctx context.Context, model T, collection string) (bool, error)
+	FindOne(ctx context.Context, filter Filter, collection string) (T, error)
+	Find(ctx context.Context, filter Filter, opts FindOptions, collection string) ([]T, error)
+	// FindPage returns one page of matches using keyset pagination, so deep
+	// pages cost the same as the first.
+	FindPage(ctx context.Context, filter Filter, opts PageOptions, collection string) (domain.Page[T], error)
+	Count(ctx context.Context, filter Filter, collection string) (int64, error)
+	GetAll(ctx context.Context, collection string) ([]T, error)
+	GetByField(ctx context.Context, field string, field_value string, collection string) (T, error)
+	GetAllByField(ctx context.Context, field string, field_value string, collection string) ([]T, error)
+	// Update replaces the first matching document and reports whether one matched.
+	Update(ctx context.Context, filter Filter, model T, collection string) (bool, error)
+	// Upsert replaces the first matching document or inserts model, and
+	// reports whether it was inserted.
+	Upsert(ctx context.Context, filter Filter, model T, collection string) (bool, error)
+	// SetFields sets only the given top-level fields of the first matching
+	// document, leaving concurrent changes to its other fields intact.
+	SetFields(ctx context.Context, filter Filter, fields map[string]interface{}, collection string) (bool, error)
+	Delete(ctx context.Context, filter Filter, collection string) (bool, error)
+	UpdateByField(ctx context.Context, field string, field_value string, model T, collection string) (bool, error)
+	DeleteByField(ctx context.Context, field string, field_value string, collection string) (bool, error)
+}
+
+// RawRepository is the escape hatch for queries the Filter builder cannot
+// express. Filters and pipelines are BSON documents such as bson.D, and
+// results are decoded into the slice that results points to.
+type RawRepository interface {
+	FindRaw(ctx context.Context, filter interface{}, results interface{}, collection string) error
+	AggregateRaw(ctx context.Context, pipeline interface{}, results interface{}, collection string) error
 }
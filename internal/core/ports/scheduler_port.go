@@ -0,0 +1,12 @@
+package ports
+
+// Job is a unit of work run by the scheduler.
+type Job interface {
+	Name() string
+	Run() error
+}
+
+// Notifier pushes a payload to every client subscribed to topic.
+type Notifier interface {
+	Publish(topic string, data interface{})
+}
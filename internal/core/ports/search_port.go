@@ -0,0 +1,10 @@
+package ports
+
+import (
+	"context"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+)
+
+type SearchService interface {
+	Search(ctx context.Context, query string, opts domain.SearchOptions) ([]domain.SearchHit, error)
+}
@@ -1,11 +1,18 @@
 package ports
 
-import "github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+import (
+	"context"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+)
 
 type UserService interface {
-	Signup(user domain.User) (string, error)
-	Login(domain.User) (domain.AccessToken, error)
+	Signup(ctx context.Context, user domain.User) (string, error)
+	Login(ctx context.Context, credentials domain.User) (domain.AccessToken, error)
 	GetLLMResponse(string)(string, error)
+	GetProfile(ctx context.Context, username string) (domain.Profile, error)
+	UpdateProfile(ctx context.Context, username string, input domain.ProfileInput) (domain.Profile, error)
+	SetPreferredLanguage(ctx context.Context, username string, lang string) error
+	ApplyPreferences(ctx context.Context, username string, ask domain.AskData) domain.AskData
 }
 
 type UserRepository interface {
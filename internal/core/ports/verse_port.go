@@ -0,0 +1,33 @@
+package ports
+
+import (
+	"context"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"time"
+)
+
+type VerseService interface {
+	GetChapters(ctx context.Context) ([]domain.Chapter, error)
+	GetChapter(ctx context.Context, number int) (domain.ChapterWithVerses, error)
+	GetVerse(ctx context.Context, ref domain.VerseRef) (domain.Verse, error)
+	GetVerseRange(ctx context.Context, verseRange domain.VerseRange) ([]domain.Verse, error)
+	Import(ctx context.Context, chapters []domain.Chapter, verses []domain.Verse) error
+}
+
+type VerseOfTheDayService interface {
+	Today(ctx context.Context) (domain.VerseOfTheDayWithText, error)
+	ForDate(ctx context.Context, date time.Time) (domain.VerseOfTheDayWithText, error)
+}
+
+type VerseRepository interface {
+	BaseRepository[domain.Verse]
+	GetChapters(ctx context.Context) ([]domain.Chapter, error)
+	GetChapter(ctx context.Context, number int) (domain.Chapter, error)
+	GetVerse(ctx context.Context, ref domain.VerseRef) (domain.Verse, error)
+	GetVerseRange(ctx context.Context, verseRange domain.VerseRange, limit int64) ([]domain.Verse, error)
+	UpsertChapter(ctx context.Context, chapter domain.Chapter) error
+	UpsertVerse(ctx context.Context, verse domain.Verse) error
+	GetVerseRefs(ctx context.Context) ([]domain.VerseRef, error)
+	GetVerseOfTheDay(ctx context.Context, date string) (domain.VerseOfTheDay, error)
+	SaveVerseOfTheDay(ctx context.Context, entry domain.VerseOfTheDay) error
+}
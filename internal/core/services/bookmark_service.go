@@ -0,0 +1,254 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+	"github.com/asifrahaman13/bhagabad_gita/internal/helper"
+	"github.com/google/uuid"
+	"sort"
+	"strings"
+	"time"
+)
+
+const BOOKMARKS_COLLECTION = "bookmarks"
+
+const (
+	EXPORT_FORMAT_JSON     = "json"
+	EXPORT_FORMAT_MARKDOWN = "markdown"
+)
+
+// ErrInvalidBookmark wraps validation failures so handlers can answer 400.
+var ErrInvalidBookmark = errors.New("invalid bookmark")
+
+type bookmarkService struct {
+	repo ports.BookmarkRepository
+}
+
+func InitializeBookmarkService(r ports.BookmarkRepository) *bookmarkService {
+	return &bookmarkService{
+		repo: r,
+	}
+}
+
+func (s *bookmarkService) Create(ctx context.Context, username string, input domain.BookmarkInput) (domain.Bookmark, error) {
+	if input.Target == nil {
+		return domain.Bookmark{}, fmt.Errorf("%w: target is required", ErrInvalidBookmark)
+	}
+	now := time.Now().UTC()
+	bookmark := domain.Bookmark{
+		Id:        uuid.New().String(),
+		Username:  username,
+		Tags:      []string{},
+		CreatedAt: now,
+	}
+	if err := applyBookmarkInput(&bookmark, input); err != nil {
+		return domain.Bookmark{}, err
+	}
+	bookmark.UpdatedAt = now
+	if _, err := s.repo.Create(ctx, bookmark, BOOKMARKS_COLLECTION); err != nil {
+		return domain.Bookmark{}, err
+	}
+	return bookmark, nil
+}
+
+func (s *bookmarkService) Get(ctx context.Context, username string, id string) (domain.Bookmark, error) {
+	bookmark, err := s.repo.GetByField(ctx, "id", id, BOOKMARKS_COLLECTION)
+	if err != nil {
+		return domain.Bookmark{}, err
+	}
+	// Other users' bookmarks are reported as missing rather than forbidden.
+	if bookmark.Username != username {
+		return domain.Bookmark{}, domain.ErrNotFound
+	}
+	return bookmark, nil
+}
+
+// BOOKMARK_SORT_FIELDS and BOOKMARK_FIELDS are what list requests may sort
+// on and project to.
+var (
+	BOOKMARK_SORT_FIELDS = []string{"createdAt", "updatedAt"}
+	BOOKMARK_FIELDS      = []string{"id", "target", "title", "note", "tags", "createdAt", "updatedAt"}
+)
+
+// List returns a page of the user's bookmarks, newest first unless another
+// sort is requested. With a search query only bookmarks whose title, note or
+// tags contain every query term as a whole word are kept, ranked by how
+// often the terms occur.
+func (s *bookmarkService) List(ctx context.Context, username string, query domain.BookmarkQuery) (domain.Page[domain.Bookmark], error) {
+	opts, err := pageOptions(query.PageQuery, ports.Desc("createdAt"), BOOKMARK_SORT_FIELDS, BOOKMARK_FIELDS)
+	if err != nil {
+		return domain.Page[domain.Bookmark]{}, err
+	}
+	filter := ports.Where("username", username)
+	if tag := normalizeTag(query.Tag); tag != "" {
+		filter = filter.And("tags", tag)
+	}
+	if strings.TrimSpace(query.Search) == "" {
+		return s.repo.FindPage(ctx, filter, opts, BOOKMARKS_COLLECTION)
+	}
+	if query.Sort != "" || query.Fields != "" {
+		return domain.Page[domain.Bookmark]{}, fmt.Errorf("%w: search results are ranked by relevance and cannot be sorted or projected", ErrInvalidPage)
+	}
+	terms := helper.QueryTerms(query.Search)
+	if len(terms) == 0 {
+		// The query held only stopwords or short words, so nothing can match.
+		return offsetPage([]domain.Bookmark{}, query.Cursor, opts.Limit)
+	}
+	bookmarks, err := s.repo.Find(ctx, filter, ports.FindOptions{Sort: opts.Sort}, BOOKMARKS_COLLECTION)
+	if err != nil {
+		return domain.Page[domain.Bookmark]{}, err
+	}
+	scores := make(map[string]int)
+	matched := []domain.Bookmark{}
+	for _, bookmark := range bookmarks {
+		score := matchScore(bookmark, terms)
+		if score == 0 {
+			continue
+		}
+		scores[bookmark.Id] = score
+		matched = append(matched, bookmark)
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return scores[matched[i].Id] > scores[matched[j].Id]
+	})
+	return offsetPage(matched, query.Cursor, opts.Limit)
+}
+
+func (s *bookmarkService) Update(ctx context.Context, username string, id string, input domain.BookmarkInput) (domain.Bookmark, error) {
+	bookmark, err := s.Get(ctx, username, id)
+	if err != nil {
+		return domain.Bookmark{}, err
+	}
+	if err := applyBookmarkInput(&bookmark, input); err != nil {
+		return domain.Bookmark{}, err
+	}
+	bookmark.UpdatedAt = time.Now().UTC()
+	updated, err := s.repo.UpdateByField(ctx, "id", id, bookmark, BOOKMARKS_COLLECTION)
+	if err != nil {
+		return domain.Bookmark{}, err
+	}
+	if !updated {
+		return domain.Bookmark{}, domain.ErrNotFound
+	}
+	return bookmark, nil
+}
+
+func (s *bookmarkService) Delete(ctx context.Context, username string, id string) error {
+	if _, err := s.Get(ctx, username, id); err != nil {
+		return err
+	}
+	deleted, err := s.repo.DeleteByField(ctx, "id", id, BOOKMARKS_COLLECTION)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// Export renders all of the user's bookmarks as JSON or Markdown and returns
+// the content together with its content type.
+func (s *bookmarkService) Export(ctx context.Context, username string, format string) ([]byte, string, error) {
+	opts := ports.FindOptions{Sort: []ports.SortField{ports.Desc("createdAt")}}
+	bookmarks, err := s.repo.Find(ctx, ports.Where("username", username), opts, BOOKMARKS_COLLECTION)
+	if err != nil {
+		return nil, "", err
+	}
+	switch format {
+	case "", EXPORT_FORMAT_JSON:
+		content, err := json.MarshalIndent(bookmarks, "", "  ")
+		return content, "application/json", err
+	case EXPORT_FORMAT_MARKDOWN:
+		return exportMarkdown(bookmarks), "text/markdown; charset=utf-8", nil
+	}
+	return nil, "", fmt.Errorf("%w: unsupported export format %q", ErrInvalidBookmark, format)
+}
+
+func exportMarkdown(bookmarks []domain.Bookmark) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("# Bookmarks\n")
+	for _, bookmark := range bookmarks {
+		title := bookmark.Title
+		if title == "" {
+			title = describeTarget(bookmark.Target)
+		}
+		fmt.Fprintf(&buffer, "\n## %s\n\n", title)
+		fmt.Fprintf(&buffer, "- Target: %s\n", describeTarget(bookmark.Target))
+		if len(bookmark.Tags) > 0 {
+			fmt.Fprintf(&buffer, "- Tags: %s\n", strings.Join(bookmark.Tags, ", "))
+		}
+		fmt.Fprintf(&buffer, "- Saved: %s\n", bookmark.CreatedAt.Format(time.DateOnly))
+		if bookmark.Note != "" {
+			fmt.Fprintf(&buffer, "\n%s\n", bookmark.Note)
+		}
+	}
+	return buffer.Bytes()
+}
+
+func describeTarget(target domain.BookmarkTarget) string {
+	if target.ChunkId != "" && target.Chapter == 0 {
+		return "Passage " + target.ChunkId
+	}
+	return "Bhagavad Gita " + domain.VerseRef{Chapter: target.Chapter, Verse: target.Verse}.String()
+}
+
+func applyBookmarkInput(bookmark *domain.Bookmark, input domain.BookmarkInput) error {
+	if input.Target != nil {
+		if err := input.Target.Validate(); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidBookmark, err)
+		}
+		bookmark.Target = *input.Target
+	}
+	if input.Title != nil {
+		bookmark.Title = strings.TrimSpace(*input.Title)
+	}
+	if input.Note != nil {
+		bookmark.Note = *input.Note
+	}
+	if input.Tags != nil {
+		tags := []string{}
+		for _, tag := range input.Tags {
+			if tag = normalizeTag(tag); tag != "" && !containsTag(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		bookmark.Tags = tags
+	}
+	return nil
+}
+
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// matchScore counts whole-word occurrences, so "art" does not match "heart".
+func matchScore(bookmark domain.Bookmark, terms []string) int {
+	counts := make(map[string]int)
+	for _, word := range helper.Words(bookmark.Title + " " + bookmark.Note + " " + strings.Join(bookmark.Tags, " ")) {
+		counts[word]++
+	}
+	score := 0
+	for _, term := range terms {
+		count := counts[term]
+		if count == 0 {
+			return 0
+		}
+		score += count
+	}
+	return score
+}
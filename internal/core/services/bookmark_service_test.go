@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/repository"
+	"reflect"
+	"testing"
+)
+
+func TestBookmarkSearch(t *testing.T) {
+	ctx := context.Background()
+	s := InitializeBookmarkService((&repository.BookmarkRepository{}).Initialize(repository.NewMemoryStore()))
+	titles := map[string]string{}
+	for i, input := range []struct{ title, note string }{
+		{"The art of action", "Karma yoga is action without attachment."},
+		{"A steady heart", "The wise are not moved by pleasure or pain."},
+		{"Karma", "Karma, karma and more karma."},
+		{"Devotion", "Surrender every action to me."},
+	} {
+		title, note := input.title, input.note
+		bookmark, err := s.Create(ctx, "arjuna", domain.BookmarkInput{
+			Target: &domain.BookmarkTarget{Chapter: 2, Verse: 47 + i},
+			Title:  &title,
+			Note:   &note,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		titles[bookmark.Id] = title
+	}
+	tests := []struct {
+		search string
+		want   []string
+	}{
+		{search: "art", want: []string{"The art of action"}},
+		{search: "heart", want: []string{"A steady heart"}},
+		{search: "action", want: []string{"The art of action", "Devotion"}},
+		{search: "karma", want: []string{"Karma", "The art of action"}},
+		{search: "karma attachment", want: []string{"The art of action"}},
+		{search: "act", want: []string{}},
+		{search: "the of", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.search, func(t *testing.T) {
+			page, err := s.List(ctx, "arjuna", domain.BookmarkQuery{Search: tt.search})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, bookmark := range page.Items {
+				got = append(got, titles[bookmark.Id])
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("List(q=%q) = %q, want %q", tt.search, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+	"github.com/asifrahaman13/bhagabad_gita/internal/helper"
+	"github.com/asifrahaman13/bhagabad_gita/internal/metrics"
+	"github.com/google/uuid"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const ANSWERS_COLLECTION = "answers"
+
+// TRANSLATION_PROMPT asks the model to translate a question into English
+// before it is embedded for retrieval.
+const TRANSLATION_PROMPT = "Translate the following question from %s into English. Reply with the translation only.\n\n%s"
+
+var ANSWER_LENGTH_INSTRUCTIONS = map[string]string{
+	domain.AnswerLengthShort:  "Keep the answer to two or three sentences.",
+	domain.AnswerLengthMedium: "Answer in one or two short paragraphs.",
+	domain.AnswerLengthLong:   "Give a detailed answer of several paragraphs.",
+}
+
+// PromptTemplate turns a question and its retrieved context into a prompt.
+// The name is stored with every answer so feedback can be compared per template.
+type PromptTemplate struct {
+	Name   string
+	Format string
+}
+
+var DEFAULT_PROMPT_TEMPLATE = PromptTemplate{
+	Name:   "spiritual-expert-v1",
+	Format: "You are an expert in spiritaul answers. User has the following query. Answer the query: %s . Also you have some additional context to give better ansser: %s",
+}
+
+// PROMPT_TEMPLATES lists the templates that can be selected by name, e.g.
+// when comparing templates in the offline evaluation.
+var PROMPT_TEMPLATES = map[string]PromptTemplate{
+	DEFAULT_PROMPT_TEMPLATE.Name: DEFAULT_PROMPT_TEMPLATE,
+	"grounded-scholar-v1": {
+		Name:   "grounded-scholar-v1",
+		Format: "You are a scholar of the Bhagavad Gita. Answer the question using only the passages below and say so when they do not contain the answer. Question: %s\nPassages: %s",
+	},
+}
+
+type chatService struct {
+	llm              *LLMService
+	embeddingService *EmbeddingService
+	qdrantService    *QdrantService
+	answerRepo       ports.AnswerRepository
+	template         PromptTemplate
+	model            string
+}
+
+func InitializeChatService(llm *LLMService, embeddingService *EmbeddingService, qdrantService *QdrantService, answerRepo ports.AnswerRepository) *chatService {
+	return &chatService{
+		llm:              llm,
+		embeddingService: embeddingService,
+		qdrantService:    qdrantService,
+		answerRepo:       answerRepo,
+		template:         DEFAULT_PROMPT_TEMPLATE,
+		model:            llm.model,
+	}
+}
+
+// WithGeneration returns a copy of the service that prompts with template
+// and generates with model.
+func (s *chatService) WithGeneration(template PromptTemplate, model string) *chatService {
+	copy := *s
+	copy.template = template
+	copy.model = model
+	return &copy
+}
+
+// Ask runs the retrieve -> prompt -> generate pipeline and emits context,
+// token or sentence, and done events. Failures are returned as
+// *domain.ProtocolError so transports can forward the code to the client.
+// Completed answers are recorded under the messageId sent in the done event.
+func (s *chatService) Ask(ctx context.Context, ask domain.AskData, emit ports.ChatEmitter) error {
+	lang := ask.Lang
+	if lang == "" {
+		lang = domain.DetectLanguage(ask.Question)
+	}
+	query := s.retrievalQuery(ctx, ask.Question, lang)
+	result, err := s.qdrantService.Search(ctx, query, s.embeddingService, domain.SearchOptions{Limit: 3})
+	if err != nil {
+		if ctx.Err() != nil {
+			return &domain.ProtocolError{Code: domain.ErrorCodeCancelled, Message: "the ask was cancelled"}
+		}
+		fmt.Println("Error searching vectors:", err)
+		return &domain.ProtocolError{Code: domain.ErrorCodeRetrieval, Message: "error searching the scripture"}
+	}
+	if !emit(domain.MessageTypeContext, domain.ContextData{Passages: result}) {
+		return nil
+	}
+	prompt := s.template.Build(ask.Question, result) + answerInstructions(ask, lang)
+	text, done, err := s.generate(ctx, prompt, ask.Granularity, emit)
+	if err != nil || done == nil {
+		return err
+	}
+	done.MessageId = uuid.New().String()
+	// The answer is complete, so store it even if the client leaves now.
+	s.record(context.WithoutCancel(ctx), domain.AnswerRecord{
+		MessageId:      done.MessageId,
+		Username:       ask.Username,
+		Question:       ask.Question,
+		Lang:           lang,
+		RetrievalQuery: query,
+		Prompt:         prompt,
+		PromptTemplate: s.template.Name,
+		Model:          s.model,
+		ContextIds:     contextIds(result),
+		Answer:         text,
+		CreatedAt:      time.Now().UTC(),
+	})
+	emit(domain.MessageTypeDone, *done)
+	return nil
+}
+
+// retrievalQuery translates a non-English question into English, the
+// language of the indexed corpus. On failure the question is searched as is.
+func (s *chatService) retrievalQuery(ctx context.Context, question string, lang string) string {
+	if lang == domain.LanguageEnglish {
+		return question
+	}
+	prompt := fmt.Sprintf(TRANSLATION_PROMPT, domain.LANGUAGE_NAMES[lang], question)
+	translated, err := s.llm.Complete(ctx, s.model, prompt, "")
+	if err != nil {
+		fmt.Println("Error translating question:", err)
+		metrics.ErrorsTotal.WithLabelValues(metrics.STAGE_TRANSLATION).Inc()
+		return question
+	}
+	if translated = strings.TrimSpace(translated); translated == "" {
+		return question
+	}
+	return translated
+}
+
+// answerInstructions tells the model the answer language and any requested
+// length or translation to quote from.
+func answerInstructions(ask domain.AskData, lang string) string {
+	instructions := fmt.Sprintf("\nWrite the whole answer in %s.", domain.LANGUAGE_NAMES[lang])
+	if length, ok := ANSWER_LENGTH_INSTRUCTIONS[ask.Length]; ok {
+		instructions += " " + length
+	}
+	if ask.Translation != "" {
+		instructions += fmt.Sprintf(" When quoting a verse, quote the %s translation.", ask.Translation)
+	}
+	return instructions
+}
+
+func (s *chatService) record(ctx context.Context, record domain.AnswerRecord) {
+	if s.answerRepo == nil {
+		return
+	}
+	if _, err := s.answerRepo.Create(ctx, record, ANSWERS_COLLECTION); err != nil {
+		fmt.Println("Error storing answer:", err)
+		metrics.ErrorsTotal.WithLabelValues(metrics.STAGE_RECORD).Inc()
+	}
+}
+
+func contextIds(passages []domain.VectorSearchResult) []string {
+	ids := make([]string, 0, len(passages))
+	for _, passage := range passages {
+		ids = append(ids, passage.Id)
+	}
+	return ids
+}
+
+// ANSWER_SORT_FIELDS and ANSWER_FIELDS are what history requests may sort
+// on and project to.
+var (
+	ANSWER_SORT_FIELDS = []string{"createdAt"}
+	ANSWER_FIELDS      = []string{"messageId", "question", "lang", "answer", "promptTemplate", "model", "contextIds", "createdAt"}
+)
+
+// History returns a page of the answers given to the user, newest first.
+func (s *chatService) History(ctx context.Context, username string, query domain.PageQuery) (domain.Page[domain.AnswerRecord], error) {
+	opts, err := pageOptions(query, ports.Desc("createdAt"), ANSWER_SORT_FIELDS, ANSWER_FIELDS)
+	if err != nil {
+		return domain.Page[domain.AnswerRecord]{}, err
+	}
+	return s.answerRepo.FindPage(ctx, ports.Where("username", username), opts, ANSWERS_COLLECTION)
+}
+
+// Answer runs the same pipeline as Ask and collects its events into a
+// single response.
+func (s *chatService) Answer(ctx context.Context, ask domain.AskData) (domain.Answer, error) {
+	answer := domain.Answer{Question: ask.Question, Lang: ask.Lang}
+	if answer.Lang == "" {
+		answer.Lang = domain.DetectLanguage(ask.Question)
+	}
+	var text strings.Builder
+	start := time.Now()
+	var retrievedAt time.Time
+	ask.Granularity = domain.GranularityToken
+	err := s.Ask(ctx, ask, func(messageType string, data interface{}) bool {
+		switch messageType {
+		case domain.MessageTypeContext:
+			retrievedAt = time.Now()
+			answer.Sources = data.(domain.ContextData).Passages
+		case domain.MessageTypeToken:
+			text.WriteString(data.(domain.TokenData).Text)
+		case domain.MessageTypeSentence:
+			text.WriteString(data.(domain.SentenceData).Text)
+		case domain.MessageTypeDone:
+			answer.Usage = data.(domain.DoneData)
+			answer.MessageId = answer.Usage.MessageId
+		}
+		return true
+	})
+	if err != nil {
+		return answer, err
+	}
+	end := time.Now()
+	answer.Answer = strings.TrimSpace(text.String())
+	answer.Timings = domain.AnswerTimings{
+		RetrievalMs:  durationMillis(retrievedAt.Sub(start)),
+		GenerationMs: durationMillis(end.Sub(retrievedAt)),
+		TotalMs:      durationMillis(end.Sub(start)),
+	}
+	return answer, nil
+}
+
+func durationMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func (t PromptTemplate) Build(question string, passages []domain.VectorSearchResult) string {
+	allContext := ""
+	for _, res := range passages {
+		trimmedContent := strings.TrimSpace(res.Content)
+		allContext += trimmedContent + "\n"
+	}
+	allContext = strings.ReplaceAll(allContext, "\n", " ")
+	return fmt.Sprintf(t.Format, question, allContext)
+}
+
+// generate streams the model's answer through emit and returns the full text
+// with the final statistics. A nil DoneData means the consumer went away.
+func (s *chatService) generate(ctx context.Context, prompt string, granularity string, emit ports.ChatEmitter) (string, *domain.DoneData, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"model":  s.model,
+		"stream": true,
+		"prompt": prompt,
+	})
+	if err != nil {
+		fmt.Println("Error marshaling request:", err)
+		return "", nil, generationError(ctx, "error creating request")
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", s.llm.url, bytes.NewBuffer(body))
+	if err != nil {
+		fmt.Println("Error creating request:", err)
+		return "", nil, generationError(ctx, "error creating request")
+	}
+	req.Header.Add("Content-Type", "application/json")
+	httpClient := &http.Client{}
+	start := time.Now()
+	res, err := httpClient.Do(req)
+	if err != nil {
+		fmt.Println("Error making request:", err)
+		return "", nil, generationError(ctx, "error making request")
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		errorBody, _ := io.ReadAll(res.Body)
+		fmt.Printf("Error response from LLM (%d): %s\n", res.StatusCode, errorBody)
+		return "", nil, generationError(ctx, "error response from the model")
+	}
+
+	decoder := json.NewDecoder(res.Body)
+	chunker := helper.NewStreamChunker(granularity)
+	messageType := domain.MessageTypeSentence
+	if granularity == domain.GranularityToken {
+		messageType = domain.MessageTypeToken
+	}
+	var text strings.Builder
+	firstToken := true
+	for frames := 0; ; frames++ {
+		var chatResponse domain.ChatResponse
+		err = decoder.Decode(&chatResponse)
+		if err == io.EOF && frames > 0 {
+			// The stream ended without Ollama's final frame; flush what we have.
+			chatResponse.Done = true
+		} else if err == io.EOF {
+			fmt.Println("Error decoding response: empty stream")
+			return "", nil, generationError(ctx, "the model returned no answer")
+		} else if err != nil {
+			fmt.Println("Error decoding response:", err)
+			return "", nil, generationError(ctx, "error decoding response")
+		}
+		if chatResponse.Error != "" {
+			fmt.Println("Error generating response:", chatResponse.Error)
+			return "", nil, generationError(ctx, "error generating response")
+		}
+		if firstToken && chatResponse.Response != "" {
+			firstToken = false
+			metrics.Since(metrics.LLMTimeToFirstToken, start)
+		}
+		text.WriteString(chatResponse.Response)
+		for _, chunk := range chunker.Write(chatResponse.Response) {
+			if !emit(messageType, chunkData(messageType, chunk)) {
+				return "", nil, nil
+			}
+		}
+		if chatResponse.Done {
+			if rest := chunker.Flush(); rest != "" {
+				emit(messageType, chunkData(messageType, rest))
+			}
+			done := domain.NewDoneData(chatResponse)
+			observeUsage(done)
+			return text.String(), &done, nil
+		}
+	}
+}
+
+// chunkData wraps a chunk of the answer in the data type of its frame.
+func chunkData(messageType string, text string) interface{} {
+	if messageType == domain.MessageTypeToken {
+		return domain.TokenData{Text: text}
+	}
+	return domain.SentenceData{Text: text}
+}
+
+// observeUsage records the token counts and throughput the model reported
+// for a finished answer.
+func observeUsage(done domain.DoneData) {
+	metrics.LLMTokensTotal.WithLabelValues("prompt").Add(float64(done.PromptTokens))
+	metrics.LLMTokensTotal.WithLabelValues("completion").Add(float64(done.CompletionTokens))
+	if done.TokensPerSecond > 0 {
+		metrics.LLMTokensPerSecond.Observe(done.TokensPerSecond)
+	}
+}
+
+// generationError distinguishes a cancelled request from an upstream failure.
+// Only the latter counts as a generation error.
+func generationError(ctx context.Context, message string) error {
+	if ctx.Err() != nil {
+		return &domain.ProtocolError{Code: domain.ErrorCodeCancelled, Message: "the ask was cancelled"}
+	}
+	metrics.ErrorsTotal.WithLabelValues(metrics.STAGE_GENERATION).Inc()
+	return &domain.ProtocolError{Code: domain.ErrorCodeGeneration, Message: message}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+	"github.com/google/uuid"
+	"sort"
+	"strings"
+	"time"
+)
+
+const FEEDBACK_COLLECTION = "feedback"
+
+type feedbackService struct {
+	repo       ports.FeedbackRepository
+	answerRepo ports.AnswerRepository
+}
+
+func InitializeFeedbackService(r ports.FeedbackRepository, answerRepo ports.AnswerRepository) *feedbackService {
+	return &feedbackService{
+		repo:       r,
+		answerRepo: answerRepo,
+	}
+}
+
+func (s *feedbackService) Submit(ctx context.Context, username string, input domain.FeedbackInput) (domain.Feedback, error) {
+	if err := input.Validate(); err != nil {
+		return domain.Feedback{}, err
+	}
+	answer, err := s.answerRepo.GetByField(ctx, "messageId", input.MessageId, ANSWERS_COLLECTION)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.Feedback{}, &domain.ProtocolError{Code: domain.ErrorCodeNotFound, Message: "no answer with this messageId"}
+	}
+	if err != nil {
+		return domain.Feedback{}, err
+	}
+	feedback := domain.Feedback{
+		Id:             uuid.New().String(),
+		MessageId:      answer.MessageId,
+		Username:       username,
+		Rating:         input.Rating,
+		Reason:         strings.ToLower(strings.TrimSpace(input.Reason)),
+		Comment:        strings.TrimSpace(input.Comment),
+		Question:       answer.Question,
+		Prompt:         answer.Prompt,
+		PromptTemplate: answer.PromptTemplate,
+		Model:          answer.Model,
+		ContextIds:     answer.ContextIds,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if _, err := s.repo.Create(ctx, feedback, FEEDBACK_COLLECTION); err != nil {
+		return domain.Feedback{}, err
+	}
+	return feedback, nil
+}
+
+// Report aggregates all feedback per prompt template and model, ordered by
+// the number of ratings received.
+func (s *feedbackService) Report(ctx context.Context) (domain.FeedbackReport, error) {
+	feedback, err := s.repo.GetAll(ctx, FEEDBACK_COLLECTION)
+	if err != nil {
+		return domain.FeedbackReport{}, err
+	}
+	groups := make(map[[2]string]*domain.FeedbackGroup)
+	for _, f := range feedback {
+		key := [2]string{f.PromptTemplate, f.Model}
+		group, exists := groups[key]
+		if !exists {
+			group = &domain.FeedbackGroup{PromptTemplate: f.PromptTemplate, Model: f.Model, Reasons: map[string]int{}}
+			groups[key] = group
+		}
+		group.Total++
+		if f.Rating == domain.RatingUp {
+			group.Up++
+		} else {
+			group.Down++
+		}
+		if f.Reason != "" {
+			group.Reasons[f.Reason]++
+		}
+	}
+	report := domain.FeedbackReport{Total: len(feedback), Groups: []domain.FeedbackGroup{}}
+	for _, group := range groups {
+		group.Satisfaction = float64(group.Up) / float64(group.Total)
+		report.Groups = append(report.Groups, *group)
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		a, b := report.Groups[i], report.Groups[j]
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		if a.PromptTemplate != b.PromptTemplate {
+			return a.PromptTemplate < b.PromptTemplate
+		}
+		return a.Model < b.Model
+	})
+	return report, nil
+}
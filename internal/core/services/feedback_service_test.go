@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/repository"
+	"reflect"
+	"testing"
+)
+
+func TestFeedbackReport(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	answers := (&repository.AnswerRepository{}).Initialize(store)
+	s := InitializeFeedbackService((&repository.FeedbackRepository{}).Initialize(store), answers)
+	for _, answer := range []domain.AnswerRecord{
+		{MessageId: "m1", PromptTemplate: "verse", Model: "llama3.1"},
+		{MessageId: "m2", PromptTemplate: "verse", Model: "mistral"},
+		{MessageId: "m3", PromptTemplate: "ab", Model: "c"},
+		{MessageId: "m4", PromptTemplate: "a", Model: "bz"},
+	} {
+		if _, err := answers.Create(ctx, answer, ANSWERS_COLLECTION); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, input := range []domain.FeedbackInput{
+		{MessageId: "m1", Rating: domain.RatingUp},
+		{MessageId: "m1", Rating: domain.RatingUp, Reason: " Helpful"},
+		{MessageId: "m1", Rating: domain.RatingDown, Reason: "wrong verse"},
+		{MessageId: "m2", Rating: domain.RatingDown, Reason: "too long"},
+		{MessageId: "m2", Rating: domain.RatingDown, Reason: "Too Long "},
+		{MessageId: "m3", Rating: domain.RatingUp},
+		{MessageId: "m4", Rating: domain.RatingDown},
+	} {
+		if _, err := s.Submit(ctx, "arjuna", input); err != nil {
+			t.Fatalf("Submit(%+v): %v", input, err)
+		}
+	}
+
+	report, err := s.Report(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := domain.FeedbackReport{Total: 7, Groups: []domain.FeedbackGroup{
+		{PromptTemplate: "verse", Model: "llama3.1", Total: 3, Up: 2, Down: 1, Satisfaction: 2.0 / 3, Reasons: map[string]int{"helpful": 1, "wrong verse": 1}},
+		{PromptTemplate: "verse", Model: "mistral", Total: 2, Up: 0, Down: 2, Satisfaction: 0, Reasons: map[string]int{"too long": 2}},
+		// Groups with as many ratings are ordered by template, then model.
+		{PromptTemplate: "a", Model: "bz", Total: 1, Up: 0, Down: 1, Satisfaction: 0, Reasons: map[string]int{}},
+		{PromptTemplate: "ab", Model: "c", Total: 1, Up: 1, Down: 0, Satisfaction: 1, Reasons: map[string]int{}},
+	}}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("Report() =\n%+v\nwant\n%+v", report, want)
+	}
+}
+
+func TestFeedbackSubmitRejects(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	s := InitializeFeedbackService((&repository.FeedbackRepository{}).Initialize(store), (&repository.AnswerRepository{}).Initialize(store))
+	tests := []struct {
+		name  string
+		input domain.FeedbackInput
+		code  string
+	}{
+		{name: "unknown answer", input: domain.FeedbackInput{MessageId: "missing", Rating: domain.RatingUp}, code: domain.ErrorCodeNotFound},
+		{name: "invalid rating", input: domain.FeedbackInput{MessageId: "m1", Rating: "meh"}, code: domain.ErrorCodeInvalidMessage},
+		{name: "no message id", input: domain.FeedbackInput{Rating: domain.RatingUp}, code: domain.ErrorCodeInvalidMessage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Submit(ctx, "arjuna", tt.input)
+			var protocolErr *domain.ProtocolError
+			if !errors.As(err, &protocolErr) || protocolErr.Code != tt.code {
+				t.Errorf("Submit() error = %v, want code %s", err, tt.code)
+			}
+		})
+	}
+	report, err := s.Report(ctx)
+	if err != nil || report.Total != 0 || len(report.Groups) != 0 {
+		t.Errorf("Report() after rejected feedback = %+v, %v", report, err)
+	}
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// HEALTH_PROBE is embedded to check the embedding model end to end.
+const HEALTH_PROBE = "health check"
+
+type healthService struct {
+	checks  []ports.HealthCheck
+	timeout time.Duration
+}
+
+func InitializeHealthService(timeout time.Duration, checks ...ports.HealthCheck) *healthService {
+	return &healthService{
+		checks:  checks,
+		timeout: timeout,
+	}
+}
+
+// Ready runs every check concurrently, each bounded by the configured
+// timeout, and reports them in registration order.
+func (s *healthService) Ready(ctx context.Context) domain.HealthReport {
+	report := domain.HealthReport{
+		Status:       domain.HealthStatusOK,
+		Dependencies: make([]domain.DependencyHealth, len(s.checks)),
+	}
+	var wg sync.WaitGroup
+	for i, check := range s.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Dependencies[i] = s.run(ctx, check)
+		}()
+	}
+	wg.Wait()
+	for _, dependency := range report.Dependencies {
+		if dependency.Status != domain.HealthStatusOK {
+			report.Status = domain.HealthStatusDown
+		}
+	}
+	return report
+}
+
+func (s *healthService) run(ctx context.Context, check ports.HealthCheck) domain.DependencyHealth {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	start := time.Now()
+	details, err := check.Check(ctx)
+	result := domain.DependencyHealth{
+		Name:      check.Name(),
+		Status:    domain.HealthStatusOK,
+		LatencyMs: durationMillis(time.Since(start)),
+		Details:   details,
+	}
+	if err != nil {
+		result.Status = domain.HealthStatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// HealthChecks returns the checks for the LLM provider, the embedding model
+// and the vector store.
+func HealthChecks(llm *LLMService, embedding *EmbeddingService, qdrant *QdrantService) []ports.HealthCheck {
+	return []ports.HealthCheck{
+		generationCheck{llm: llm},
+		embeddingCheck{embedding: embedding, qdrant: qdrant},
+		qdrantCheck{qdrant: qdrant},
+	}
+}
+
+// generationCheck asks the provider which models it has pulled rather than
+// generating, which could load the model and take far longer than a probe.
+type generationCheck struct {
+	llm *LLMService
+}
+
+func (c generationCheck) Name() string {
+	return "generation"
+}
+
+func (c generationCheck) Check(ctx context.Context) (map[string]interface{}, error) {
+	details := map[string]interface{}{"model": c.llm.model}
+	tagsUrl, err := url.Parse(c.llm.url)
+	if err != nil {
+		return details, fmt.Errorf("invalid LLM url: %w", err)
+	}
+	tagsUrl.Path = "/api/tags"
+	req, err := http.NewRequestWithContext(ctx, "GET", tagsUrl.String(), nil)
+	if err != nil {
+		return details, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return details, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return details, fmt.Errorf("received non-200 HTTP response: %d", resp.StatusCode)
+	}
+	var tags struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return details, fmt.Errorf("failed to decode model list: %w", err)
+	}
+	for _, model := range tags.Models {
+		if model.Name == c.llm.model || model.Name == c.llm.model+":latest" {
+			return details, nil
+		}
+	}
+	return details, fmt.Errorf("model %q is not available", c.llm.model)
+}
+
+// embeddingCheck embeds a probe and compares its dimension with the vector
+// size of the collection it will be searched against.
+type embeddingCheck struct {
+	embedding *EmbeddingService
+	qdrant    *QdrantService
+}
+
+func (c embeddingCheck) Name() string {
+	return "embedding"
+}
+
+func (c embeddingCheck) Check(ctx context.Context) (map[string]interface{}, error) {
+	details := map[string]interface{}{"model": c.embedding.model}
+	embedding, err := c.embedding.GetEmbedding(ctx, HEALTH_PROBE)
+	if err != nil {
+		return details, err
+	}
+	details["dimension"] = len(embedding)
+	if len(embedding) == 0 {
+		return details, fmt.Errorf("embedding model returned an empty vector")
+	}
+	size, err := c.qdrant.vectorSize(ctx)
+	if err != nil {
+		// The qdrant check reports why the collection can't be read.
+		return details, nil
+	}
+	if uint64(len(embedding)) != size {
+		return details, fmt.Errorf("embedding dimension %d does not match the collection's vector size %d", len(embedding), size)
+	}
+	return details, nil
+}
+
+type qdrantCheck struct {
+	qdrant *QdrantService
+}
+
+func (c qdrantCheck) Name() string {
+	return "qdrant"
+}
+
+func (c qdrantCheck) Check(ctx context.Context) (map[string]interface{}, error) {
+	details := map[string]interface{}{"collection": c.qdrant.collection}
+	exists, err := c.qdrant.client.CollectionExists(ctx, c.qdrant.collection)
+	if err != nil {
+		return details, err
+	}
+	if !exists {
+		return details, fmt.Errorf("collection %q does not exist", c.qdrant.collection)
+	}
+	info, err := c.qdrant.client.GetCollectionInfo(ctx, c.qdrant.collection)
+	if err != nil {
+		return details, err
+	}
+	details["points"] = info.GetPointsCount()
+	if size := info.GetConfig().GetParams().GetVectorsConfig().GetParams().GetSize(); size > 0 {
+		details["vectorSize"] = size
+	}
+	return details, nil
+}
+
+// vectorSize is the size of the collection's single unnamed vector.
+func (q *QdrantService) vectorSize(ctx context.Context) (uint64, error) {
+	info, err := q.client.GetCollectionInfo(ctx, q.collection)
+	if err != nil {
+		return 0, err
+	}
+	size := info.GetConfig().GetParams().GetVectorsConfig().GetParams().GetSize()
+	if size == 0 {
+		return 0, fmt.Errorf("collection %q has no single vector configuration", q.collection)
+	}
+	return size, nil
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/config"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"io"
+	"net/http"
+)
+
+// LLMService talks to the LLM provider's generate endpoint.
+type LLMService struct {
+	url   string
+	model string
+}
+
+func NewLLMService(conf config.LLMConfig) *LLMService {
+	return &LLMService{url: conf.URL, model: conf.Model}
+}
+
+// Complete sends a non-streaming generate request to the LLM provider.
+// A non-empty format, such as "json", constrains the model's output.
+func (l *LLMService) Complete(ctx context.Context, model string, prompt string, format string) (string, error) {
+	posturl := l.url
+	payload := map[string]interface{}{
+		"model":  model,
+		"prompt": prompt,
+		"stream": false,
+	}
+	if format != "" {
+		payload["format"] = format
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", posturl, bytes.NewBuffer(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("received non-200 HTTP response: %d", resp.StatusCode)
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+	responseText := string(respBody)
+	if responseText == "" {
+		return "", errors.New("received empty response")
+	}
+	var data domain.ResponseData
+	err = json.Unmarshal([]byte(responseText), &data)
+	if err != nil {
+		return "", fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return data.Response, nil
+}
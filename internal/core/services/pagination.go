@@ -0,0 +1,74 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidPage wraps invalid pagination parameters so handlers can answer 400.
+var ErrInvalidPage = errors.New("invalid page")
+
+// pageOptions validates a PageQuery against the fields a list can be sorted
+// on and projected to. Field names are the stored names, which match the
+// JSON names of the listed type.
+func pageOptions(query domain.PageQuery, defaultSort ports.SortField, sortable []string, fields []string) (ports.PageOptions, error) {
+	opts := ports.PageOptions{Sort: []ports.SortField{defaultSort}, Limit: query.Limit, Cursor: query.Cursor}
+	if opts.Limit == 0 {
+		opts.Limit = domain.DEFAULT_PAGE_LIMIT
+	}
+	if opts.Limit < 0 || opts.Limit > domain.MAX_PAGE_LIMIT {
+		return opts, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidPage, domain.MAX_PAGE_LIMIT)
+	}
+	if query.Sort != "" {
+		field := ports.Asc(strings.TrimPrefix(query.Sort, "-"))
+		field.Descending = strings.HasPrefix(query.Sort, "-")
+		if !slices.Contains(sortable, field.Field) {
+			return opts, fmt.Errorf("%w: cannot sort by %q, expected one of %s", ErrInvalidPage, field.Field, strings.Join(sortable, ", "))
+		}
+		opts.Sort = []ports.SortField{field}
+	}
+	for _, field := range strings.Split(query.Fields, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		if !slices.Contains(fields, field) {
+			return opts, fmt.Errorf("%w: unknown field %q, expected one of %s", ErrInvalidPage, field, strings.Join(fields, ", "))
+		}
+		opts.Fields = append(opts.Fields, field)
+	}
+	return opts, nil
+}
+
+// offsetPage pages through a list that is ranked in memory, such as search
+// results, with an opaque cursor holding the offset of the next item.
+func offsetPage[T any](items []T, cursor string, limit int64) (domain.Page[T], error) {
+	offset := 0
+	if cursor != "" {
+		data, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil || !strings.HasPrefix(string(data), "offset:") {
+			return domain.Page[T]{}, domain.ErrInvalidCursor
+		}
+		offset, err = strconv.Atoi(strings.TrimPrefix(string(data), "offset:"))
+		if err != nil || offset < 0 {
+			return domain.Page[T]{}, domain.ErrInvalidCursor
+		}
+	}
+	page := domain.Page[T]{Items: []T{}}
+	if offset >= len(items) {
+		return page, nil
+	}
+	end := offset + int(limit)
+	if end < len(items) {
+		page.NextCursor = base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("offset:%d", end)))
+	} else {
+		end = len(items)
+	}
+	page.Items = append(page.Items, items[offset:end]...)
+	return page, nil
+}
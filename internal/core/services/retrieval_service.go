@@ -0,0 +1,134 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/config"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/metrics"
+	"github.com/qdrant/go-client/qdrant"
+	"io"
+	"net/http"
+	"time"
+)
+
+type EmbeddingService struct {
+	url   string
+	model string
+}
+
+type QdrantService struct {
+	client     *qdrant.Client
+	collection string
+}
+
+func NewEmbeddingService(conf config.EmbeddingConfig) *EmbeddingService {
+	return &EmbeddingService{url: conf.URL, model: conf.Model}
+}
+
+func NewQdrantService(conf config.QdrantConfig) (*QdrantService, error) {
+	client, err := qdrant.NewClient(&qdrant.Config{
+		Host: conf.Host,
+		Port: int(conf.Port),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating Qdrant client: %w", err)
+	}
+	return &QdrantService{client: client, collection: conf.Collection}, nil
+}
+
+func (q *QdrantService) Close() error {
+	return q.client.Close()
+}
+
+func (e *EmbeddingService) GetEmbedding(ctx context.Context, content string) ([]float32, error) {
+	payload := map[string]string{
+		"model":  e.model,
+		"prompt": fmt.Sprintf("Represent this sentence for searching relevant passages: %s", content),
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling payload: %v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", e.url, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return nil, fmt.Errorf("error creating embedding request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request to embedding API: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("error response from embedding API: %s", string(body))
+	}
+	var result struct {
+		Embedding []float32 `json:"embedding"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding embedding API response: %v", err)
+	}
+	return result.Embedding, nil
+}
+
+// Search embeds the query and returns the closest passages, optionally
+// restricted to a single chapter.
+func (q *QdrantService) Search(ctx context.Context, query string, embeddingService *EmbeddingService, opts domain.SearchOptions) ([]domain.VectorSearchResult, error) {
+	start := time.Now()
+	embedding, err := embeddingService.GetEmbedding(ctx, query)
+	if err != nil {
+		metrics.ErrorsTotal.WithLabelValues(metrics.STAGE_EMBEDDING).Inc()
+		return nil, err
+	}
+	metrics.Since(metrics.EmbeddingDuration, start)
+	limit := opts.Limit
+	request := &qdrant.QueryPoints{
+		CollectionName: q.collection,
+		Query:          qdrant.NewQuery(embedding...),
+		Limit:          &limit,
+		WithPayload:    qdrant.NewWithPayload(true),
+	}
+	if opts.Chapter > 0 {
+		request.Filter = &qdrant.Filter{
+			Must: []*qdrant.Condition{qdrant.NewMatchInt("chapter", int64(opts.Chapter))},
+		}
+	}
+	start = time.Now()
+	searchResult, err := q.client.Query(ctx, request)
+	if err != nil {
+		metrics.ErrorsTotal.WithLabelValues(metrics.STAGE_VECTOR_SEARCH).Inc()
+		return nil, err
+	}
+	metrics.Since(metrics.VectorSearchDuration, start)
+	var results []domain.VectorSearchResult
+	for _, res := range searchResult {
+		metrics.RetrievalScore.Observe(float64(res.Score))
+		results = append(results, domain.VectorSearchResult{
+			Id:      res.Id.GetUuid(),
+			PageNum: payloadNumber(res.Payload["pageNum"]),
+			PageIdx: payloadNumber(res.Payload["pageIdx"]),
+			Chapter: payloadNumber(res.Payload["chapter"]),
+			Verse:   payloadNumber(res.Payload["verse"]),
+			Content: res.Payload["pageContent"].GetStringValue(),
+			Score:   res.Score,
+		})
+	}
+	return results, nil
+}
+
+// payloadNumber reads a numeric payload field that may have been stored
+// either as an integer or as a double.
+func payloadNumber(value *qdrant.Value) uint64 {
+	if value == nil {
+		return 0
+	}
+	if _, ok := value.GetKind().(*qdrant.Value_IntegerValue); ok {
+		return uint64(value.GetIntegerValue())
+	}
+	return uint64(value.GetDoubleValue())
+}
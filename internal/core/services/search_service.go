@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/helper"
+)
+
+type searchService struct {
+	embeddingService *EmbeddingService
+	qdrantService    *QdrantService
+}
+
+func InitializeSearchService(embeddingService *EmbeddingService, qdrantService *QdrantService) *searchService {
+	return &searchService{
+		embeddingService: embeddingService,
+		qdrantService:    qdrantService,
+	}
+}
+
+func (s *searchService) Search(ctx context.Context, query string, opts domain.SearchOptions) ([]domain.SearchHit, error) {
+	results, err := s.qdrantService.Search(ctx, query, s.embeddingService, opts)
+	if err != nil {
+		return nil, err
+	}
+	terms := helper.QueryTerms(query)
+	hits := make([]domain.SearchHit, 0, len(results))
+	for i, result := range results {
+		highlighted, matched := helper.Highlight(result.Content, terms)
+		hits = append(hits, domain.SearchHit{
+			Rank:               i + 1,
+			VectorSearchResult: result,
+			Highlighted:        highlighted,
+			MatchedTerms:       matched,
+		})
+	}
+	return hits, nil
+}
@@ -1,43 +1,94 @@
 package service
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"crypto/subtle"
+	"errors"
 	"fmt"
 	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
 	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
 	"github.com/asifrahaman13/bhagabad_gita/internal/helper"
-	"github.com/asifrahaman13/bhagabad_gita/internal/config"
-	"io"
-	"net/http"
-	"errors"
+	"golang.org/x/crypto/bcrypt"
+	"slices"
+	"strings"
 )
 
+// ErrInvalidProfile wraps validation failures so handlers can answer 400.
+var ErrInvalidProfile = errors.New("invalid profile")
+
+// ErrInvalidSignup wraps signup validation failures so handlers can answer 400.
+var ErrInvalidSignup = errors.New("invalid signup")
+
+// MAX_PASSWORD_LENGTH is the longest password bcrypt can hash, in bytes.
+const MAX_PASSWORD_LENGTH = 72
+
+// ErrInvalidCredentials is returned by Login for an unknown username or a
+// wrong password, without saying which.
+var ErrInvalidCredentials = errors.New("invalid username or password")
+
 type userService struct {
-	repo ports.UserRepository
+	repo           ports.UserRepository
+	llm            *LLMService
+	adminUsernames []string
 }
 
-func InitializeUserService(r ports.UserRepository) *userService {
+func InitializeUserService(r ports.UserRepository, llm *LLMService, adminUsernames []string) *userService {
 	return &userService{
-		repo: r,
+		repo:           r,
+		llm:            llm,
+		adminUsernames: adminUsernames,
 	}
 }
 
-func (s *userService) Signup(user domain.User) (string, error) {
-	message, err := s.repo.Create(user, "users")
+func (s *userService) Signup(ctx context.Context, user domain.User) (string, error) {
+	if user.Username == "" || user.Password == "" {
+		return "", fmt.Errorf("%w: username and password are required", ErrInvalidSignup)
+	}
+	// bcrypt ignores everything after MAX_PASSWORD_LENGTH bytes.
+	if len(user.Password) > MAX_PASSWORD_LENGTH {
+		return "", fmt.Errorf("%w: password must be at most %d bytes", ErrInvalidSignup, MAX_PASSWORD_LENGTH)
+	}
+	if err := user.Preferences.Validate(); err != nil {
+		return "", fmt.Errorf("%w: invalid preferences: %v", ErrInvalidSignup, err)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	user.Password = string(hash)
+	created, err := s.repo.Create(ctx, user, "users")
 	if err != nil {
-		panic(err)
+		return "", err
 	}
-	if !message {
-		return "Failed to insert data", nil
+	if !created {
+		return "", errors.New("user was not stored")
 	}
 	return "Successfully stored the information", nil
 }
 
-func (s *userService) Login(user domain.User) (domain.AccessToken, error) {
-	token, err := helper.CreateToken(user.Username, "user")
+// Login checks the password against the stored hash before issuing a token.
+// Admin tokens go only to ADMIN_USERNAMES that pass the same check.
+func (s *userService) Login(ctx context.Context, credentials domain.User) (domain.AccessToken, error) {
+	if credentials.Username == "" || credentials.Password == "" {
+		return domain.AccessToken{}, ErrInvalidCredentials
+	}
+	user, err := s.getUser(ctx, credentials.Username)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.AccessToken{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return domain.AccessToken{}, err
+	}
+	if !s.checkPassword(ctx, user, credentials.Password) {
+		return domain.AccessToken{}, ErrInvalidCredentials
+	}
+	userType := "user"
+	if slices.Contains(s.adminUsernames, user.Username) {
+		userType = "admin"
+	}
+	token, err := helper.CreateToken(user.Username, userType)
 	if err != nil {
-		panic(err)
+		return domain.AccessToken{}, err
 	}
 	accessToken := domain.AccessToken{
 		Token: token,
@@ -45,47 +96,96 @@ func (s *userService) Login(user domain.User) (domain.AccessToken, error) {
 	return accessToken, nil
 }
 
-func (s *userService) GetLLMResponse(query string) (string, error) {
-	config, err := config.NewConfig() 
+// checkPassword compares password with the stored bcrypt hash. Accounts
+// created before passwords were hashed still hold the plain text; it is
+// compared in constant time and replaced by its hash on the first login.
+func (s *userService) checkPassword(ctx context.Context, user domain.User, password string) bool {
+	if strings.HasPrefix(user.Password, "$2") {
+		return bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) == nil
+	}
+	if subtle.ConstantTimeCompare([]byte(user.Password), []byte(password)) != 1 {
+		return false
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err == nil {
+		_, err = s.repo.SetFields(ctx, ports.Where("username", user.Username), map[string]interface{}{"password": string(hash)}, "users")
+	}
 	if err != nil {
-		return "", fmt.Errorf("failed to get config: %w", err)
+		fmt.Println("Error hashing stored password:", err)
 	}
-	posturl := config.LLamaUrl
-	payload := map[string]interface{}{
-		"model":  "llama3.1",
-		"prompt": query,
-		"stream": false,
+	return true
+}
+
+func (s *userService) GetLLMResponse(query string) (string, error) {
+	return s.llm.Complete(context.Background(), s.llm.model, query, "")
+}
+
+func (s *userService) getUser(ctx context.Context, username string) (domain.User, error) {
+	return s.repo.GetByField(ctx, "username", username, "users")
+}
+
+func (s *userService) GetProfile(ctx context.Context, username string) (domain.Profile, error) {
+	user, err := s.getUser(ctx, username)
+	if err != nil {
+		return domain.Profile{}, err
 	}
-	body, err := json.Marshal(payload)
+	return domain.NewProfile(user), nil
+}
+
+func (s *userService) UpdateProfile(ctx context.Context, username string, input domain.ProfileInput) (domain.Profile, error) {
+	user, err := s.getUser(ctx, username)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal payload: %w", err)
+		return domain.Profile{}, err
 	}
-	req, err := http.NewRequest("POST", posturl, bytes.NewBuffer(body))
+	user, err = input.ApplyTo(user)
 	if err != nil {
-		return "", fmt.Errorf("failed to create HTTP request: %w", err)
+		return domain.Profile{}, fmt.Errorf("%w: %v", ErrInvalidProfile, err)
+	}
+	fields := input.Fields(user)
+	if len(fields) == 0 {
+		return domain.NewProfile(user), nil
 	}
-	req.Header.Set("Content-Type", "application/json")
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	// Only the fields in the input are written, so a password rehashed or a
+	// language stored by a concurrent request is not overwritten.
+	updated, err := s.repo.SetFields(ctx, ports.Where("username", username), fields, "users")
 	if err != nil {
-		return "", fmt.Errorf("failed to execute HTTP request: %w", err)
+		return domain.Profile{}, err
+	}
+	if !updated {
+		return domain.Profile{}, domain.ErrNotFound
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("received non-200 HTTP response: %d", resp.StatusCode)
+	return domain.NewProfile(user), nil
+}
+
+func (s *userService) SetPreferredLanguage(ctx context.Context, username string, lang string) error {
+	if !domain.IsValidLanguage(lang) {
+		return fmt.Errorf("unsupported language %q", lang)
 	}
-	respBody, err := io.ReadAll(resp.Body)
+	// Only the one field is written, so a concurrent PATCH /v1/me is not lost.
+	updated, err := s.repo.SetFields(ctx, ports.Where("username", username), map[string]interface{}{"preferredLanguage": lang}, "users")
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		return err
 	}
-	responseText := string(respBody)
-	if responseText == "" {
-		return "", errors.New("received empty response")
+	if !updated {
+		return domain.ErrNotFound
 	}
-	var data domain.ResponseData
-	err = json.Unmarshal([]byte(responseText), &data)
+	return nil
+}
+
+// ApplyPreferences fills what the ask leaves unset from the user's
+// preferences and remembers an explicitly requested language as their new
+// preferred language. Lookup failures leave the ask unchanged.
+func (s *userService) ApplyPreferences(ctx context.Context, username string, ask domain.AskData) domain.AskData {
+	user, err := s.getUser(ctx, username)
 	if err != nil {
-		return "", fmt.Errorf("failed to unmarshal response: %w", err)
+		fmt.Println("Error getting user:", err)
+		return ask
 	}
-	return data.Response, nil
-}
\ No newline at end of file
+	// Asks in the language already preferred, the common case, write nothing.
+	if ask.Lang != "" && ask.Lang != user.Preferences.Language {
+		if err := s.SetPreferredLanguage(ctx, username, ask.Lang); err != nil {
+			fmt.Println("Error storing preferred language:", err)
+		}
+	}
+	return user.Preferences.Apply(ask)
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+	"github.com/asifrahaman13/bhagabad_gita/internal/helper"
+	"github.com/asifrahaman13/bhagabad_gita/internal/repository"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestUserService(t *testing.T) (*userService, *repository.UserRepository) {
+	t.Helper()
+	helper.InitializeTokens("test-secret", time.Hour)
+	repo := (&repository.UserRepository{}).Initialize(repository.NewMemoryStore())
+	return InitializeUserService(repo, nil, []string{"krishna"}), repo
+}
+
+func signup(t *testing.T, s *userService, user domain.User) {
+	t.Helper()
+	message, err := s.Signup(context.Background(), user)
+	if err != nil || message != "Successfully stored the information" {
+		t.Fatalf("Signup(%s) = %q, %v", user.Username, message, err)
+	}
+}
+
+func userType(t *testing.T, token domain.AccessToken) interface{} {
+	t.Helper()
+	claims, err := helper.VerifyToken(token.Token)
+	if err != nil {
+		t.Fatalf("VerifyToken: %v", err)
+	}
+	return claims["user_type"]
+}
+
+func TestSignupAndLogin(t *testing.T) {
+	ctx := context.Background()
+	s, repo := newTestUserService(t)
+	signup(t, s, domain.User{Username: "arjuna", Email: "arjuna@example.com", Password: "gandiva"})
+	signup(t, s, domain.User{Username: "krishna", Password: "flute"})
+
+	stored, err := repo.GetByField(ctx, "username", "arjuna", "users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(stored.Password, "$2") {
+		t.Errorf("stored password = %q, want a bcrypt hash", stored.Password)
+	}
+
+	token, err := s.Login(ctx, domain.User{Username: "arjuna", Password: "gandiva"})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if got := userType(t, token); got != "user" {
+		t.Errorf("user_type = %v, want user", got)
+	}
+	token, err = s.Login(ctx, domain.User{Username: "krishna", Password: "flute"})
+	if err != nil {
+		t.Fatalf("admin Login: %v", err)
+	}
+	if got := userType(t, token); got != "admin" {
+		t.Errorf("admin user_type = %v, want admin", got)
+	}
+
+	for _, credentials := range []domain.User{
+		{Username: "arjuna", Password: "wrong"},
+		{Username: "arjuna"},
+		{Username: "krishna", Password: "wrong"},
+		{Username: "karna", Password: "gandiva"},
+	} {
+		if _, err := s.Login(ctx, credentials); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("Login(%s, %q): err = %v, want ErrInvalidCredentials", credentials.Username, credentials.Password, err)
+		}
+	}
+}
+
+func TestSignupRejects(t *testing.T) {
+	s, _ := newTestUserService(t)
+	signup(t, s, domain.User{Username: "arjuna", Email: "arjuna@example.com", Password: "gandiva"})
+	tests := []struct {
+		name string
+		user domain.User
+		want error
+	}{
+		{name: "no password", user: domain.User{Username: "bhima"}, want: ErrInvalidSignup},
+		{name: "password too long", user: domain.User{Username: "bhima", Password: strings.Repeat("a", MAX_PASSWORD_LENGTH+1)}, want: ErrInvalidSignup},
+		{name: "invalid preferences", user: domain.User{Username: "bhima", Password: "mace", Preferences: domain.Preferences{Language: "xx"}}, want: ErrInvalidSignup},
+		{name: "taken username", user: domain.User{Username: "arjuna", Password: "other"}, want: domain.ErrDuplicate},
+		{name: "taken email", user: domain.User{Username: "bhima", Email: "arjuna@example.com", Password: "mace"}, want: domain.ErrDuplicate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if message, err := s.Signup(context.Background(), tt.user); !errors.Is(err, tt.want) {
+				t.Errorf("Signup() = %q, %v, want %v", message, err, tt.want)
+			}
+		})
+	}
+	// The longest password bcrypt accepts still signs up and logs in.
+	password := strings.Repeat("a", MAX_PASSWORD_LENGTH)
+	signup(t, s, domain.User{Username: "bhima", Password: password})
+	if _, err := s.Login(context.Background(), domain.User{Username: "bhima", Password: password}); err != nil {
+		t.Errorf("Login with a %d byte password: %v", MAX_PASSWORD_LENGTH, err)
+	}
+}
+
+func TestLoginRehashesPlainTextPassword(t *testing.T) {
+	ctx := context.Background()
+	s, repo := newTestUserService(t)
+	if _, err := repo.Create(ctx, domain.User{Username: "arjuna", Password: "gandiva"}, "users"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Login(ctx, domain.User{Username: "arjuna", Password: "wrong"}); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("wrong password: err = %v, want ErrInvalidCredentials", err)
+	}
+	if _, err := s.Login(ctx, domain.User{Username: "arjuna", Password: "gandiva"}); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	stored, err := repo.GetByField(ctx, "username", "arjuna", "users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(stored.Password, "$2") {
+		t.Errorf("stored password = %q, want it rehashed", stored.Password)
+	}
+	if _, err := s.Login(ctx, domain.User{Username: "arjuna", Password: "gandiva"}); err != nil {
+		t.Errorf("Login after rehash: %v", err)
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestUserService(t)
+	signup(t, s, domain.User{Username: "arjuna", Email: "arjuna@example.com", Password: "gandiva"})
+
+	name, lang := "  Partha ", domain.LanguageHindi
+	profile, err := s.UpdateProfile(ctx, "arjuna", domain.ProfileInput{DisplayName: &name, Language: &lang})
+	if err != nil {
+		t.Fatalf("UpdateProfile: %v", err)
+	}
+	want := domain.Profile{
+		Username:    "arjuna",
+		Email:       "arjuna@example.com",
+		DisplayName: "Partha",
+		Preferences: domain.Preferences{Language: domain.LanguageHindi},
+	}
+	if profile != want {
+		t.Errorf("UpdateProfile() = %+v, want %+v", profile, want)
+	}
+	if profile, err := s.GetProfile(ctx, "arjuna"); err != nil || profile != want {
+		t.Errorf("GetProfile() = %+v, %v, want %+v", profile, err, want)
+	}
+	// Updating the profile keeps the password hash.
+	if _, err := s.Login(ctx, domain.User{Username: "arjuna", Password: "gandiva"}); err != nil {
+		t.Errorf("Login after UpdateProfile: %v", err)
+	}
+
+	invalid := "xx"
+	if _, err := s.UpdateProfile(ctx, "arjuna", domain.ProfileInput{Language: &invalid}); !errors.Is(err, ErrInvalidProfile) {
+		t.Errorf("invalid language: err = %v, want ErrInvalidProfile", err)
+	}
+	if _, err := s.UpdateProfile(ctx, "karna", domain.ProfileInput{DisplayName: &name}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("unknown user: err = %v, want ErrNotFound", err)
+	}
+	if _, err := s.GetProfile(ctx, "karna"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetProfile of unknown user: err = %v, want ErrNotFound", err)
+	}
+}
+
+// racingUserRepo runs write after the first read, as a request running
+// concurrently with the reader would.
+type racingUserRepo struct {
+	ports.UserRepository
+	write func()
+}
+
+func (r *racingUserRepo) GetByField(ctx context.Context, field string, value string, collection string) (domain.User, error) {
+	user, err := r.UserRepository.GetByField(ctx, field, value, collection)
+	if r.write != nil {
+		r.write()
+		r.write = nil
+	}
+	return user, err
+}
+
+func TestUpdateProfileKeepsConcurrentWrites(t *testing.T) {
+	ctx := context.Background()
+	s, repo := newTestUserService(t)
+	signup(t, s, domain.User{Username: "arjuna", Password: "gandiva"})
+	racing := &racingUserRepo{UserRepository: repo, write: func() {
+		if err := s.SetPreferredLanguage(ctx, "arjuna", domain.LanguageBengali); err != nil {
+			t.Fatal(err)
+		}
+	}}
+	name := "Partha"
+	racer := InitializeUserService(racing, nil, nil)
+	if _, err := racer.UpdateProfile(ctx, "arjuna", domain.ProfileInput{DisplayName: &name}); err != nil {
+		t.Fatalf("UpdateProfile: %v", err)
+	}
+	profile, err := s.GetProfile(ctx, "arjuna")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.DisplayName != "Partha" || profile.Preferences.Language != domain.LanguageBengali {
+		t.Errorf("profile = %+v, want the display name and the concurrently stored language", profile)
+	}
+}
+
+func TestApplyPreferences(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestUserService(t)
+	signup(t, s, domain.User{
+		Username:    "arjuna",
+		Password:    "gandiva",
+		Preferences: domain.Preferences{Language: domain.LanguageHindi, AnswerLength: "short"},
+	})
+
+	ask := s.ApplyPreferences(ctx, "arjuna", domain.AskData{Question: "What is dharma?"})
+	if ask.Lang != domain.LanguageHindi || ask.Length != "short" {
+		t.Errorf("ApplyPreferences() = %+v, want the stored preferences", ask)
+	}
+
+	ask = s.ApplyPreferences(ctx, "arjuna", domain.AskData{Question: "What is dharma?", Lang: domain.LanguageBengali})
+	if ask.Lang != domain.LanguageBengali {
+		t.Errorf("explicit language was replaced: %+v", ask)
+	}
+	profile, err := s.GetProfile(ctx, "arjuna")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.Preferences.Language != domain.LanguageBengali || profile.Preferences.AnswerLength != "short" {
+		t.Errorf("preferences after an explicit language = %+v", profile.Preferences)
+	}
+
+	ask = s.ApplyPreferences(ctx, "karna", domain.AskData{Question: "Who am I?"})
+	if ask != (domain.AskData{Question: "Who am I?"}) {
+		t.Errorf("unknown user: ask = %+v, want it unchanged", ask)
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+	"math/rand"
+	"time"
+)
+
+// verseOfTheDayService changes the verse at midnight in location, the
+// scheduler's timezone, so the job and the endpoint agree on the date.
+type verseOfTheDayService struct {
+	repo     ports.VerseRepository
+	notifier ports.Notifier
+	location *time.Location
+}
+
+func InitializeVerseOfTheDayService(r ports.VerseRepository, notifier ports.Notifier, location *time.Location) *verseOfTheDayService {
+	return &verseOfTheDayService{
+		repo:     r,
+		notifier: notifier,
+		location: location,
+	}
+}
+
+func (s *verseOfTheDayService) Today(ctx context.Context) (domain.VerseOfTheDayWithText, error) {
+	return s.ForDate(ctx, time.Now())
+}
+
+// ForDate returns the stored pick for the date, picking and storing it first
+// if the scheduled job has not run yet.
+func (s *verseOfTheDayService) ForDate(ctx context.Context, date time.Time) (domain.VerseOfTheDayWithText, error) {
+	day := date.In(s.location).Format(time.DateOnly)
+	entry, err := s.repo.GetVerseOfTheDay(ctx, day)
+	if errors.Is(err, domain.ErrNotFound) {
+		entry, err = s.pick(ctx, date)
+		if err == nil {
+			err = s.repo.SaveVerseOfTheDay(ctx, entry)
+		}
+	}
+	if err != nil {
+		return domain.VerseOfTheDayWithText{}, err
+	}
+	verse, err := s.repo.GetVerse(ctx, domain.VerseRef{Chapter: entry.Chapter, Verse: entry.Verse})
+	if err != nil {
+		return domain.VerseOfTheDayWithText{}, err
+	}
+	return domain.VerseOfTheDayWithText{VerseOfTheDay: entry, Text: verse}, nil
+}
+
+// pick chooses the verse for a date. Days are grouped into cycles as long as
+// the corpus; each cycle walks a permutation seeded by the cycle number, so
+// the choice is deterministic per date and no verse repeats within a cycle.
+func (s *verseOfTheDayService) pick(ctx context.Context, date time.Time) (domain.VerseOfTheDay, error) {
+	refs, err := s.repo.GetVerseRefs(ctx)
+	if err != nil {
+		return domain.VerseOfTheDay{}, err
+	}
+	if len(refs) == 0 {
+		return domain.VerseOfTheDay{}, domain.ErrNotFound
+	}
+	// Count local calendar days, so the pick changes at local midnight.
+	year, month, dayOfMonth := date.In(s.location).Date()
+	days := time.Date(year, month, dayOfMonth, 0, 0, 0, 0, time.UTC).Unix() / int64(24*time.Hour/time.Second)
+	cycle := days / int64(len(refs))
+	position := days % int64(len(refs))
+	order := rand.New(rand.NewSource(cycle)).Perm(len(refs))
+	ref := refs[order[position]]
+	return domain.VerseOfTheDay{
+		Date:    date.In(s.location).Format(time.DateOnly),
+		Chapter: ref.Chapter,
+		Verse:   ref.Verse,
+	}, nil
+}
+
+func (s *verseOfTheDayService) Name() string {
+	return "verse-of-the-day"
+}
+
+// Run is the scheduled job: it stores today's verse and pushes it to
+// subscribed websocket clients.
+func (s *verseOfTheDayService) Run() error {
+	today, err := s.Today(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to pick the verse of the day: %w", err)
+	}
+	s.notifier.Publish(domain.TopicVerseOfTheDay, today)
+	return nil
+}
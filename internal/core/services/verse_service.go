@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+)
+
+// MAX_VERSE_RANGE caps how many verses a single range query returns.
+const MAX_VERSE_RANGE = 100
+
+type verseService struct {
+	repo ports.VerseRepository
+}
+
+func InitializeVerseService(r ports.VerseRepository) *verseService {
+	return &verseService{
+		repo: r,
+	}
+}
+
+func (s *verseService) GetChapters(ctx context.Context) ([]domain.Chapter, error) {
+	return s.repo.GetChapters(ctx)
+}
+
+func (s *verseService) GetChapter(ctx context.Context, number int) (domain.ChapterWithVerses, error) {
+	chapter, err := s.repo.GetChapter(ctx, number)
+	if err != nil {
+		return domain.ChapterWithVerses{}, err
+	}
+	verses, err := s.repo.GetVerseRange(ctx, domain.VerseRange{
+		From: domain.VerseRef{Chapter: number, Verse: 1},
+		To:   domain.VerseRef{Chapter: number, Verse: chapter.VerseCount},
+	}, MAX_VERSE_RANGE)
+	if err != nil {
+		return domain.ChapterWithVerses{}, err
+	}
+	return domain.ChapterWithVerses{Chapter: chapter, Verses: verses}, nil
+}
+
+func (s *verseService) GetVerse(ctx context.Context, ref domain.VerseRef) (domain.Verse, error) {
+	return s.repo.GetVerse(ctx, ref)
+}
+
+func (s *verseService) GetVerseRange(ctx context.Context, verseRange domain.VerseRange) ([]domain.Verse, error) {
+	verses, err := s.repo.GetVerseRange(ctx, verseRange, MAX_VERSE_RANGE)
+	if err != nil {
+		return nil, err
+	}
+	if len(verses) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return verses, nil
+}
+
+// Import upserts chapters and verses, so re-running ingestion is safe.
+// Chapter verse counts are derived from the verses when not provided.
+func (s *verseService) Import(ctx context.Context, chapters []domain.Chapter, verses []domain.Verse) error {
+	counts := make(map[int]int)
+	for _, verse := range verses {
+		if err := s.repo.UpsertVerse(ctx, verse); err != nil {
+			return fmt.Errorf("failed to store verse %d.%d: %w", verse.Chapter, verse.Verse, err)
+		}
+		if verse.Verse > counts[verse.Chapter] {
+			counts[verse.Chapter] = verse.Verse
+		}
+	}
+	for _, chapter := range chapters {
+		if chapter.VerseCount == 0 {
+			chapter.VerseCount = counts[chapter.Number]
+		}
+		if err := s.repo.UpsertChapter(ctx, chapter); err != nil {
+			return fmt.Errorf("failed to store chapter %d: %w", chapter.Number, err)
+		}
+	}
+	return nil
+}
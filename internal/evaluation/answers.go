@@ -0,0 +1,147 @@
+package evaluation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"strings"
+)
+
+// Variant is one prompt template and generation model combination.
+type Variant struct {
+	PromptTemplate string `json:"promptTemplate"`
+	Model          string `json:"model"`
+}
+
+// Answerer runs the chat pipeline for a question with the given variant.
+type Answerer func(ctx context.Context, question string, variant Variant) (domain.Answer, error)
+
+// Completer sends a prompt to the judge model and returns its raw output.
+type Completer func(ctx context.Context, model string, prompt string) (string, error)
+
+type JudgeScore struct {
+	// Score is the judge's 1-5 rating normalised to 0-1.
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason"`
+}
+
+type AnswerResult struct {
+	Id           string     `json:"id"`
+	Question     string     `json:"question"`
+	Answer       string     `json:"answer"`
+	ContextIds   []string   `json:"contextIds"`
+	Faithfulness JudgeScore `json:"faithfulness"`
+	Relevance    JudgeScore `json:"relevance"`
+	Error        string     `json:"error,omitempty"`
+}
+
+type VariantReport struct {
+	Variant
+	Answered     int            `json:"answered"`
+	Failed       int            `json:"failed"`
+	Faithfulness float64        `json:"faithfulness"`
+	Relevance    float64        `json:"relevance"`
+	Results      []AnswerResult `json:"results"`
+}
+
+type AnswerReport struct {
+	GoldenSet  string          `json:"goldenSet"`
+	JudgeModel string          `json:"judgeModel"`
+	Variants   []VariantReport `json:"variants"`
+}
+
+const faithfulnessPrompt = `You are grading an answer generated from retrieved passages of the Bhagavad Gita.
+Rate from 1 to 5 how faithful the answer is to the passages: 5 means every claim is supported by them, 1 means the answer contradicts them or is unsupported.
+Reply with JSON only: {"score": <1-5>, "reason": "<one sentence>"}.
+
+Passages:
+%s
+
+Answer:
+%s`
+
+const relevancePrompt = `You are grading an answer to a question about the Bhagavad Gita.
+Rate from 1 to 5 how well the answer addresses the question: 5 means it answers it directly and completely, 1 means it is off topic.
+Reply with JSON only: {"score": <1-5>, "reason": "<one sentence>"}.
+%s
+Question:
+%s
+
+Answer:
+%s`
+
+// EvaluateAnswers generates an answer for every golden question with every
+// variant and has the judge model score faithfulness and relevance.
+// Failures are recorded per question rather than aborting the run.
+func EvaluateAnswers(ctx context.Context, set GoldenSet, variants []Variant, answer Answerer, judgeModel string, complete Completer) (AnswerReport, error) {
+	report := AnswerReport{GoldenSet: set.Name, JudgeModel: judgeModel}
+	for _, variant := range variants {
+		variantReport := VariantReport{Variant: variant, Results: []AnswerResult{}}
+		for _, q := range set.Questions {
+			result := AnswerResult{Id: q.Id, Question: q.Question, ContextIds: []string{}}
+			if err := judgeQuestion(ctx, q, variant, answer, judgeModel, complete, &result); err != nil {
+				if ctx.Err() != nil {
+					return report, ctx.Err()
+				}
+				result.Error = err.Error()
+				variantReport.Failed++
+			} else {
+				variantReport.Answered++
+				variantReport.Faithfulness += result.Faithfulness.Score
+				variantReport.Relevance += result.Relevance.Score
+			}
+			variantReport.Results = append(variantReport.Results, result)
+		}
+		if variantReport.Answered > 0 {
+			variantReport.Faithfulness = round(variantReport.Faithfulness / float64(variantReport.Answered))
+			variantReport.Relevance = round(variantReport.Relevance / float64(variantReport.Answered))
+		}
+		report.Variants = append(report.Variants, variantReport)
+	}
+	return report, nil
+}
+
+func judgeQuestion(ctx context.Context, q GoldenQuestion, variant Variant, answer Answerer, judgeModel string, complete Completer, result *AnswerResult) error {
+	generated, err := answer(ctx, q.Question, variant)
+	if err != nil {
+		return fmt.Errorf("answer generation failed: %w", err)
+	}
+	result.Answer = generated.Answer
+	var passages strings.Builder
+	for _, source := range generated.Sources {
+		result.ContextIds = append(result.ContextIds, source.Id)
+		fmt.Fprintf(&passages, "- %s\n", strings.TrimSpace(source.Content))
+	}
+	result.Faithfulness, err = judge(ctx, complete, judgeModel, fmt.Sprintf(faithfulnessPrompt, passages.String(), generated.Answer))
+	if err != nil {
+		return fmt.Errorf("faithfulness judgement failed: %w", err)
+	}
+	reference := ""
+	if q.ReferenceAnswer != "" {
+		reference = fmt.Sprintf("A reference answer is: %s\n", q.ReferenceAnswer)
+	}
+	result.Relevance, err = judge(ctx, complete, judgeModel, fmt.Sprintf(relevancePrompt, reference, q.Question, generated.Answer))
+	if err != nil {
+		return fmt.Errorf("relevance judgement failed: %w", err)
+	}
+	return nil
+}
+
+func judge(ctx context.Context, complete Completer, model string, prompt string) (JudgeScore, error) {
+	output, err := complete(ctx, model, prompt)
+	if err != nil {
+		return JudgeScore{}, err
+	}
+	var verdict struct {
+		Score  float64 `json:"score"`
+		Reason string  `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &verdict); err != nil {
+		return JudgeScore{}, fmt.Errorf("judge returned invalid JSON %q: %w", output, err)
+	}
+	if verdict.Score < 1 || verdict.Score > 5 {
+		return JudgeScore{}, fmt.Errorf("judge score %v is outside 1-5", verdict.Score)
+	}
+	return JudgeScore{Score: round((verdict.Score - 1) / 4), Reason: verdict.Reason}, nil
+}
@@ -0,0 +1,88 @@
+package evaluation
+
+import (
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExpectedPassage identifies a relevant passage either by chapter and verse
+// or by page number of the ingested PDF.
+type ExpectedPassage struct {
+	Chapter uint64 `json:"chapter,omitempty" yaml:"chapter,omitempty"`
+	Verse   uint64 `json:"verse,omitempty" yaml:"verse,omitempty"`
+	Page    uint64 `json:"page,omitempty" yaml:"page,omitempty"`
+}
+
+func (e ExpectedPassage) String() string {
+	if e.Page > 0 {
+		return fmt.Sprintf("page %d", e.Page)
+	}
+	return fmt.Sprintf("%d.%d", e.Chapter, e.Verse)
+}
+
+type GoldenQuestion struct {
+	Id       string            `json:"id" yaml:"id"`
+	Question string            `json:"question" yaml:"question"`
+	Expected []ExpectedPassage `json:"expected" yaml:"expected"`
+	// ReferenceAnswer is optional and only used by answer-quality evaluation.
+	ReferenceAnswer string `json:"referenceAnswer,omitempty" yaml:"referenceAnswer,omitempty"`
+}
+
+type GoldenSet struct {
+	Name      string           `json:"name" yaml:"name"`
+	Questions []GoldenQuestion `json:"questions" yaml:"questions"`
+}
+
+// LoadGoldenSet reads a golden question set from a .json, .yaml or .yml file.
+func LoadGoldenSet(path string) (GoldenSet, error) {
+	var set GoldenSet
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return set, fmt.Errorf("failed to read golden set: %w", err)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &set)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &set)
+	default:
+		return set, fmt.Errorf("unsupported golden set format %q, use .json, .yaml or .yml", filepath.Ext(path))
+	}
+	if err != nil {
+		return set, fmt.Errorf("failed to parse golden set: %w", err)
+	}
+	if set.Name == "" {
+		set.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return set, set.validate()
+}
+
+func (s GoldenSet) validate() error {
+	if len(s.Questions) == 0 {
+		return fmt.Errorf("golden set %q has no questions", s.Name)
+	}
+	seen := make(map[string]bool)
+	for i, q := range s.Questions {
+		if q.Id == "" || q.Question == "" {
+			return fmt.Errorf("question %d needs an id and a question", i+1)
+		}
+		if seen[q.Id] {
+			return fmt.Errorf("duplicate question id %q", q.Id)
+		}
+		seen[q.Id] = true
+		// Without expected passages every retrieval metric would silently be 0.
+		if len(q.Expected) == 0 {
+			return fmt.Errorf("question %q has no expected passages", q.Id)
+		}
+		for _, expected := range q.Expected {
+			if expected.Page == 0 && (expected.Chapter == 0 || expected.Verse == 0) {
+				return fmt.Errorf("question %q has an expected passage without a page or chapter and verse", q.Id)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package evaluation
+
+import (
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"math"
+)
+
+// matches reports whether a retrieved passage is the expected one.
+func matches(expected ExpectedPassage, passage domain.VectorSearchResult) bool {
+	if expected.Page > 0 {
+		return passage.PageNum == expected.Page
+	}
+	return passage.Chapter == expected.Chapter && passage.Verse == expected.Verse
+}
+
+// relevance marks each retrieved passage as relevant or not. A relevant
+// passage only counts once, so duplicates don't inflate the scores.
+func relevance(expected []ExpectedPassage, retrieved []domain.VectorSearchResult) []bool {
+	found := make([]bool, len(expected))
+	relevant := make([]bool, len(retrieved))
+	for i, passage := range retrieved {
+		for j, e := range expected {
+			if !found[j] && matches(e, passage) {
+				found[j] = true
+				relevant[i] = true
+				break
+			}
+		}
+	}
+	return relevant
+}
+
+func recallAtK(relevant []bool, expectedCount int) float64 {
+	if expectedCount == 0 {
+		return 0
+	}
+	hits := 0
+	for _, r := range relevant {
+		if r {
+			hits++
+		}
+	}
+	return float64(hits) / float64(expectedCount)
+}
+
+func reciprocalRank(relevant []bool) float64 {
+	for i, r := range relevant {
+		if r {
+			return 1 / float64(i+1)
+		}
+	}
+	return 0
+}
+
+// ndcgAtK uses binary relevance; the ideal ranking puts every expected
+// passage first.
+func ndcgAtK(relevant []bool, expectedCount int) float64 {
+	dcg := 0.0
+	for i, r := range relevant {
+		if r {
+			dcg += 1 / math.Log2(float64(i+2))
+		}
+	}
+	ideal := 0.0
+	for i := 0; i < expectedCount && i < len(relevant); i++ {
+		ideal += 1 / math.Log2(float64(i+2))
+	}
+	if ideal == 0 {
+		return 0
+	}
+	return dcg / ideal
+}
+
+// round keeps reports stable and readable when diffed between runs.
+func round(value float64) float64 {
+	return math.Round(value*10000) / 10000
+}
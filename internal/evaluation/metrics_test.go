@@ -0,0 +1,93 @@
+package evaluation
+
+import (
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"math"
+	"strings"
+	"testing"
+)
+
+func verse(chapter, verse uint64) domain.VectorSearchResult {
+	return domain.VectorSearchResult{Chapter: chapter, Verse: verse}
+}
+
+func TestRetrievalMetrics(t *testing.T) {
+	tests := []struct {
+		name      string
+		expected  []ExpectedPassage
+		retrieved []domain.VectorSearchResult
+		recall    float64
+		mrr       float64
+		ndcg      float64
+	}{
+		{
+			name:      "single hit at rank 1",
+			expected:  []ExpectedPassage{{Chapter: 2, Verse: 20}},
+			retrieved: []domain.VectorSearchResult{verse(2, 20), verse(2, 21), verse(2, 22)},
+			recall:    1,
+			mrr:       1,
+			ndcg:      1,
+		},
+		{
+			name:      "no hits",
+			expected:  []ExpectedPassage{{Chapter: 18, Verse: 66}},
+			retrieved: []domain.VectorSearchResult{verse(2, 20), verse(2, 21)},
+			recall:    0,
+			mrr:       0,
+			ndcg:      0,
+		},
+		{
+			// Hits at ranks 2 and 4, and the duplicate of 2.47 at rank 5 does
+			// not count again.
+			// DCG   = 1/log2(3) + 1/log2(5)        = 0.63093 + 0.43068 = 1.06161
+			// IDCG  = 1/log2(2) + 1/log2(3) + 1/log2(4) = 1 + 0.63093 + 0.5 = 2.13093
+			name:      "partial hits with a duplicate",
+			expected:  []ExpectedPassage{{Chapter: 2, Verse: 47}, {Chapter: 3, Verse: 19}, {Chapter: 18, Verse: 66}},
+			retrieved: []domain.VectorSearchResult{verse(1, 1), verse(2, 47), verse(1, 2), verse(3, 19), verse(2, 47)},
+			recall:    2.0 / 3,
+			mrr:       0.5,
+			ndcg:      1.06161 / 2.13093,
+		},
+		{
+			// Only two passages fit in k=2, so the ideal ranking is two hits.
+			name:      "ideal ranking limited to k",
+			expected:  []ExpectedPassage{{Chapter: 2, Verse: 55}, {Chapter: 2, Verse: 56}, {Chapter: 2, Verse: 57}},
+			retrieved: []domain.VectorSearchResult{verse(2, 56), verse(2, 55)},
+			recall:    2.0 / 3,
+			mrr:       1,
+			ndcg:      1,
+		},
+		{
+			name:      "page ids",
+			expected:  []ExpectedPassage{{Page: 12}},
+			retrieved: []domain.VectorSearchResult{{PageNum: 3}, {PageNum: 12}},
+			recall:    1,
+			mrr:       0.5,
+			ndcg:      1 / math.Log2(3),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			relevant := relevance(tt.expected, tt.retrieved)
+			check := func(metric string, got, want float64) {
+				if math.Abs(got-want) > 1e-4 {
+					t.Errorf("%s = %.5f, want %.5f", metric, got, want)
+				}
+			}
+			check("recall@k", recallAtK(relevant, len(tt.expected)), tt.recall)
+			check("MRR", reciprocalRank(relevant), tt.mrr)
+			check("nDCG@k", ndcgAtK(relevant, len(tt.expected)), tt.ndcg)
+		})
+	}
+}
+
+func TestValidateRejectsQuestionWithoutExpectedPassages(t *testing.T) {
+	set := GoldenSet{Name: "test", Questions: []GoldenQuestion{
+		{Id: "karma-yoga", Question: "What is karma yoga?", Expected: []ExpectedPassage{{Chapter: 2, Verse: 47}}},
+		{Id: "no-answer", Question: "Who wrote the Gita?"},
+	}}
+	err := set.validate()
+	if err == nil || !strings.Contains(err.Error(), `"no-answer"`) {
+		t.Fatalf("validate() = %v, want an error naming question no-answer", err)
+	}
+}
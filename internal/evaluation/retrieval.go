@@ -0,0 +1,117 @@
+package evaluation
+
+import (
+	"context"
+	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+)
+
+// Retriever returns the top k passages for a question.
+type Retriever func(ctx context.Context, question string, k uint64) ([]domain.VectorSearchResult, error)
+
+type RetrievalMetrics struct {
+	RecallAtK float64 `json:"recallAtK"`
+	MRR       float64 `json:"mrr"`
+	NDCGAtK   float64 `json:"ndcgAtK"`
+}
+
+type QuestionResult struct {
+	Id        string   `json:"id"`
+	Question  string   `json:"question"`
+	Expected  []string `json:"expected"`
+	Retrieved []string `json:"retrieved"`
+	Hits      int      `json:"hits"`
+	RetrievalMetrics
+}
+
+// RetrievalReport is written as JSON. It carries no timestamps so that two
+// runs over the same corpus produce identical output.
+type RetrievalReport struct {
+	GoldenSet string           `json:"goldenSet"`
+	K         uint64           `json:"k"`
+	Questions int              `json:"questions"`
+	Mean      RetrievalMetrics `json:"mean"`
+	Results   []QuestionResult `json:"results"`
+}
+
+func EvaluateRetrieval(ctx context.Context, set GoldenSet, k uint64, retrieve Retriever) (RetrievalReport, error) {
+	report := RetrievalReport{GoldenSet: set.Name, K: k, Questions: len(set.Questions)}
+	var total RetrievalMetrics
+	for _, q := range set.Questions {
+		retrieved, err := retrieve(ctx, q.Question, k)
+		if err != nil {
+			return report, fmt.Errorf("retrieval failed for question %q: %w", q.Id, err)
+		}
+		if uint64(len(retrieved)) > k {
+			retrieved = retrieved[:k]
+		}
+		relevant := relevance(q.Expected, retrieved)
+		result := QuestionResult{
+			Id:        q.Id,
+			Question:  q.Question,
+			Expected:  []string{},
+			Retrieved: []string{},
+			RetrievalMetrics: RetrievalMetrics{
+				RecallAtK: round(recallAtK(relevant, len(q.Expected))),
+				MRR:       round(reciprocalRank(relevant)),
+				NDCGAtK:   round(ndcgAtK(relevant, len(q.Expected))),
+			},
+		}
+		for _, e := range q.Expected {
+			result.Expected = append(result.Expected, e.String())
+		}
+		for i, passage := range retrieved {
+			result.Retrieved = append(result.Retrieved, describePassage(passage))
+			if relevant[i] {
+				result.Hits++
+			}
+		}
+		total.RecallAtK += result.RecallAtK
+		total.MRR += result.MRR
+		total.NDCGAtK += result.NDCGAtK
+		report.Results = append(report.Results, result)
+	}
+	count := float64(len(set.Questions))
+	report.Mean = RetrievalMetrics{
+		RecallAtK: round(total.RecallAtK / count),
+		MRR:       round(total.MRR / count),
+		NDCGAtK:   round(total.NDCGAtK / count),
+	}
+	return report, nil
+}
+
+func describePassage(passage domain.VectorSearchResult) string {
+	if passage.Chapter > 0 {
+		return fmt.Sprintf("%d.%d", passage.Chapter, passage.Verse)
+	}
+	return fmt.Sprintf("page %d", passage.PageNum)
+}
+
+// Thresholds are minimum acceptable mean scores. Zero disables a check.
+type Thresholds struct {
+	RecallAtK float64
+	MRR       float64
+	NDCGAtK   float64
+}
+
+// CheckRegression lists every metric below its absolute threshold or, when
+// a baseline is given, more than tolerance below the baseline.
+func CheckRegression(current RetrievalMetrics, thresholds Thresholds, baseline *RetrievalMetrics, tolerance float64) []string {
+	var failures []string
+	check := func(name string, value float64, minimum float64, previous float64) {
+		if minimum > 0 && value < minimum {
+			failures = append(failures, fmt.Sprintf("%s %.4f is below the threshold %.4f", name, value, minimum))
+		}
+		if baseline != nil && value < previous-tolerance {
+			failures = append(failures, fmt.Sprintf("%s %.4f regressed from baseline %.4f", name, value, previous))
+		}
+	}
+	var previous RetrievalMetrics
+	if baseline != nil {
+		previous = *baseline
+	}
+	check("recall@k", current.RecallAtK, thresholds.RecallAtK, previous.RecallAtK)
+	check("MRR", current.MRR, thresholds.MRR, previous.MRR)
+	check("nDCG@k", current.NDCGAtK, thresholds.NDCGAtK, previous.NDCGAtK)
+	return failures
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+	service "github.com/asifrahaman13/bhagabad_gita/internal/core/services"
+	"github.com/asifrahaman13/bhagabad_gita/internal/helper"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+var BookmarkHandler *bookmarkHandler
+
+type bookmarkHandler struct {
+	bookmarkService ports.BookmarkService
+}
+
+func (h *bookmarkHandler) Initialize(bookmarkService ports.BookmarkService) {
+	BookmarkHandler = &bookmarkHandler{
+		bookmarkService: bookmarkService,
+	}
+}
+
+func (h *bookmarkHandler) Create(c *gin.Context) {
+	username, ok := helper.CurrentUsername(c)
+	if !ok {
+		helper.JSONResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	var input domain.BookmarkInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		helper.JSONResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	bookmark, err := h.bookmarkService.Create(c.Request.Context(), username, input)
+	if err != nil {
+		respondBookmarkError(c, err)
+		return
+	}
+	helper.JSONResponse(c, http.StatusCreated, bookmark, nil)
+}
+
+// List handles GET /v1/bookmarks?tag=...&q=..., where q searches titles,
+// notes and tags.
+func (h *bookmarkHandler) List(c *gin.Context) {
+	username, ok := helper.CurrentUsername(c)
+	if !ok {
+		helper.JSONResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	var query domain.BookmarkQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		helper.JSONResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	page, err := h.bookmarkService.List(c.Request.Context(), username, query)
+	if err != nil {
+		respondBookmarkError(c, err)
+		return
+	}
+	helper.JSONResponse(c, http.StatusOK, page, nil)
+}
+
+func (h *bookmarkHandler) Get(c *gin.Context) {
+	username, ok := helper.CurrentUsername(c)
+	if !ok {
+		helper.JSONResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	bookmark, err := h.bookmarkService.Get(c.Request.Context(), username, c.Param("id"))
+	if err != nil {
+		respondBookmarkError(c, err)
+		return
+	}
+	helper.JSONResponse(c, http.StatusOK, bookmark, nil)
+}
+
+func (h *bookmarkHandler) Update(c *gin.Context) {
+	username, ok := helper.CurrentUsername(c)
+	if !ok {
+		helper.JSONResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	var input domain.BookmarkInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		helper.JSONResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	bookmark, err := h.bookmarkService.Update(c.Request.Context(), username, c.Param("id"), input)
+	if err != nil {
+		respondBookmarkError(c, err)
+		return
+	}
+	helper.JSONResponse(c, http.StatusOK, bookmark, nil)
+}
+
+func (h *bookmarkHandler) Delete(c *gin.Context) {
+	username, ok := helper.CurrentUsername(c)
+	if !ok {
+		helper.JSONResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	if err := h.bookmarkService.Delete(c.Request.Context(), username, c.Param("id")); err != nil {
+		respondBookmarkError(c, err)
+		return
+	}
+	helper.JSONResponse(c, http.StatusOK, "Bookmark deleted", nil)
+}
+
+// Export handles GET /v1/bookmarks/export?format=json|markdown and serves
+// the result as a download.
+func (h *bookmarkHandler) Export(c *gin.Context) {
+	username, ok := helper.CurrentUsername(c)
+	if !ok {
+		helper.JSONResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	format := c.DefaultQuery("format", service.EXPORT_FORMAT_JSON)
+	content, contentType, err := h.bookmarkService.Export(c.Request.Context(), username, format)
+	if err != nil {
+		respondBookmarkError(c, err)
+		return
+	}
+	extension := "json"
+	if format == service.EXPORT_FORMAT_MARKDOWN {
+		extension = "md"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="bookmarks.%s"`, extension))
+	c.Data(http.StatusOK, contentType, content)
+}
+
+func respondBookmarkError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		helper.JSONResponse(c, http.StatusNotFound, "Bookmark not found", nil)
+	case errors.Is(err, service.ErrInvalidBookmark), errors.Is(err, service.ErrInvalidPage), errors.Is(err, domain.ErrInvalidCursor):
+		helper.JSONResponse(c, http.StatusBadRequest, err.Error(), nil)
+	default:
+		fmt.Println("Error handling bookmark:", err)
+		helper.JSONResponse(c, http.StatusInternalServerError, "Error handling bookmark", nil)
+	}
+}
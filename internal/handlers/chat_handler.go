@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+	service "github.com/asifrahaman13/bhagabad_gita/internal/core/services"
+	"github.com/asifrahaman13/bhagabad_gita/internal/helper"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+var ChatHandler *chatHandler
+
+type chatHandler struct {
+	chatService ports.ChatService
+	userService ports.UserService
+}
+
+func (h *chatHandler) Initialize(chatService ports.ChatService, userService ports.UserService) {
+	ChatHandler = &chatHandler{
+		chatService: chatService,
+		userService: userService,
+	}
+}
+
+func (h *chatHandler) applyPreferences(c *gin.Context, ask *domain.AskData) {
+	if username, ok := helper.CurrentUsername(c); ok {
+		*ask = h.userService.ApplyPreferences(c.Request.Context(), username, *ask)
+		ask.Username = username
+	}
+}
+
+// Stream answers a question over Server-Sent Events. Each event is named
+// after the websocket message type and carries the same data payload.
+func (h *chatHandler) Stream(c *gin.Context) {
+	var ask domain.AskData
+	if err := c.ShouldBindJSON(&ask); err != nil {
+		helper.JSONResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if err := ask.Validate(); err != nil {
+		helper.JSONResponse(c, http.StatusBadRequest, errorData(err), nil)
+		return
+	}
+	h.applyPreferences(c, &ask)
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	ctx := c.Request.Context()
+	emit := func(messageType string, data interface{}) bool {
+		if ctx.Err() != nil {
+			return false
+		}
+		c.Render(-1, sse.Event{
+			Id:    uuid.New().String(),
+			Event: messageType,
+			Data:  data,
+		})
+		c.Writer.Flush()
+		return true
+	}
+	if err := h.chatService.Ask(ctx, ask, emit); err != nil {
+		fmt.Println("Error streaming answer:", err)
+		emit(domain.MessageTypeError, errorData(err))
+	}
+}
+
+// Ask answers a question in one response. POST takes a JSON body; GET takes
+// the question from the query string, e.g. /v1/ask?question=...&lang=hi
+func (h *chatHandler) Ask(c *gin.Context) {
+	var ask domain.AskData
+	if err := c.ShouldBind(&ask); err != nil {
+		helper.JSONResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if ask.Question == "" {
+		ask.Question = c.Query("q")
+	}
+	if err := ask.Validate(); err != nil {
+		helper.JSONResponse(c, http.StatusBadRequest, errorData(err), nil)
+		return
+	}
+	h.applyPreferences(c, &ask)
+	answer, err := h.chatService.Answer(c.Request.Context(), ask)
+	if err != nil {
+		fmt.Println("Error answering question:", err)
+		helper.JSONResponse(c, errorStatus(err), errorData(err), nil)
+		return
+	}
+	helper.JSONResponse(c, http.StatusOK, answer, nil)
+}
+
+// History handles GET /v1/history?limit=...&cursor=...&fields=...
+func (h *chatHandler) History(c *gin.Context) {
+	username, ok := helper.CurrentUsername(c)
+	if !ok {
+		helper.JSONResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	var query domain.PageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		helper.JSONResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	page, err := h.chatService.History(c.Request.Context(), username, query)
+	if errors.Is(err, service.ErrInvalidPage) || errors.Is(err, domain.ErrInvalidCursor) {
+		helper.JSONResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		fmt.Println("Error reading history:", err)
+		helper.JSONResponse(c, http.StatusInternalServerError, "Error reading history", nil)
+		return
+	}
+	helper.JSONResponse(c, http.StatusOK, page, nil)
+}
+
+func errorStatus(err error) int {
+	var protocolErr *domain.ProtocolError
+	if !errors.As(err, &protocolErr) {
+		return http.StatusInternalServerError
+	}
+	switch protocolErr.Code {
+	case domain.ErrorCodeInvalidMessage:
+		return http.StatusBadRequest
+	case domain.ErrorCodeNotFound:
+		return http.StatusNotFound
+	case domain.ErrorCodeRetrieval, domain.ErrorCodeGeneration:
+		return http.StatusBadGateway
+	case domain.ErrorCodeCancelled:
+		return http.StatusRequestTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+func errorData(err error) domain.ErrorData {
+	var protocolErr *domain.ProtocolError
+	if errors.As(err, &protocolErr) {
+		return domain.ErrorData{Code: protocolErr.Code, Message: protocolErr.Message}
+	}
+	return domain.ErrorData{Code: domain.ErrorCodeInternal, Message: "internal server error"}
+}
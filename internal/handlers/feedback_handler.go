@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+	"github.com/asifrahaman13/bhagabad_gita/internal/helper"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+var FeedbackHandler *feedbackHandler
+
+type feedbackHandler struct {
+	feedbackService ports.FeedbackService
+}
+
+func (h *feedbackHandler) Initialize(feedbackService ports.FeedbackService) {
+	FeedbackHandler = &feedbackHandler{
+		feedbackService: feedbackService,
+	}
+}
+
+// Submit records a rating for an answer. Authentication is optional; when a
+// valid token is sent the feedback is attributed to that user.
+func (h *feedbackHandler) Submit(c *gin.Context) {
+	var input domain.FeedbackInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		helper.JSONResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	username, _ := helper.CurrentUsername(c)
+	feedback, err := h.feedbackService.Submit(c.Request.Context(), username, input)
+	if err != nil {
+		fmt.Println("Error storing feedback:", err)
+		helper.JSONResponse(c, errorStatus(err), errorData(err), nil)
+		return
+	}
+	helper.JSONResponse(c, http.StatusCreated, feedback, nil)
+}
+
+func (h *feedbackHandler) Report(c *gin.Context) {
+	report, err := h.feedbackService.Report(c.Request.Context())
+	if err != nil {
+		fmt.Println("Error building feedback report:", err)
+		helper.JSONResponse(c, http.StatusInternalServerError, "Error building feedback report", nil)
+		return
+	}
+	helper.JSONResponse(c, http.StatusOK, report, nil)
+}
@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
 	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+	service "github.com/asifrahaman13/bhagabad_gita/internal/core/services"
 	"github.com/asifrahaman13/bhagabad_gita/internal/helper"
 	"github.com/gin-gonic/gin"
+	"net/http"
 )
 
 var UserHandler *userHandler
@@ -23,19 +26,32 @@ func (h *userHandler) Initialize(userserv ports.UserService) {
 func (h *userHandler) Signup(c *gin.Context) {
 	var user domain.User
 	c.BindJSON(&user)
-	message, err := h.userService.Signup(user)
-	if err != nil {
-		panic(err)
+	message, err := h.userService.Signup(c.Request.Context(), user)
+	switch {
+	case errors.Is(err, service.ErrInvalidSignup):
+		helper.JSONResponse(c, http.StatusBadRequest, err.Error(), nil)
+	case errors.Is(err, domain.ErrDuplicate):
+		helper.JSONResponse(c, http.StatusConflict, "Username or email is already registered", nil)
+	case err != nil:
+		fmt.Println("Error signing up:", err)
+		helper.JSONResponse(c, http.StatusInternalServerError, "Error signing up", nil)
+	default:
+		helper.JSONResponse(c, 200, message, nil)
 	}
-	helper.JSONResponse(c, 200, message, nil)
 }
 
 func (h *userHandler) Login(c *gin.Context) {
 	var user domain.User
 	c.BindJSON(&user)
-	message, err := h.userService.Login(user)
+	message, err := h.userService.Login(c.Request.Context(), user)
+	if errors.Is(err, service.ErrInvalidCredentials) {
+		helper.JSONResponse(c, http.StatusUnauthorized, "Invalid username or password", nil)
+		return
+	}
 	if err != nil {
-		panic(err)
+		fmt.Println("Error logging in:", err)
+		helper.JSONResponse(c, http.StatusInternalServerError, "Error logging in", nil)
+		return
 	}
 	helper.JSONResponse(c, 200, message, nil)
 }
@@ -52,3 +68,48 @@ func (s *userHandler) PublicApi(c *gin.Context) {
 	message["message"] = llmResponse
 	helper.JSONResponse(c, 200, message, nil)
 }
+
+func (h *userHandler) GetMe(c *gin.Context) {
+	username, ok := helper.CurrentUsername(c)
+	if !ok {
+		helper.JSONResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	profile, err := h.userService.GetProfile(c.Request.Context(), username)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+	helper.JSONResponse(c, http.StatusOK, profile, nil)
+}
+
+func (h *userHandler) UpdateMe(c *gin.Context) {
+	username, ok := helper.CurrentUsername(c)
+	if !ok {
+		helper.JSONResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	var input domain.ProfileInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		helper.JSONResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	profile, err := h.userService.UpdateProfile(c.Request.Context(), username, input)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+	helper.JSONResponse(c, http.StatusOK, profile, nil)
+}
+
+func respondProfileError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		helper.JSONResponse(c, http.StatusNotFound, "User not found", nil)
+	case errors.Is(err, service.ErrInvalidProfile):
+		helper.JSONResponse(c, http.StatusBadRequest, err.Error(), nil)
+	default:
+		fmt.Println("Error handling profile:", err)
+		helper.JSONResponse(c, http.StatusInternalServerError, "Error handling profile", nil)
+	}
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+var HealthHandler *healthHandler
+
+type healthHandler struct {
+	healthService ports.HealthService
+}
+
+func (h *healthHandler) Initialize(healthService ports.HealthService) {
+	HealthHandler = &healthHandler{
+		healthService: healthService,
+	}
+}
+
+// Live handles GET /healthz. It only reports that the process is serving
+// requests and never checks dependencies.
+func (h *healthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, domain.HealthReport{Status: domain.HealthStatusOK})
+}
+
+// Ready handles GET /readyz, answering 503 when any dependency is down.
+func (h *healthHandler) Ready(c *gin.Context) {
+	report := h.healthService.Ready(c.Request.Context())
+	code := http.StatusOK
+	if report.Status != domain.HealthStatusOK {
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, report)
+}
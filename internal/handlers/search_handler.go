@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+	"github.com/asifrahaman13/bhagabad_gita/internal/helper"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+const (
+	DEFAULT_SEARCH_LIMIT = 5
+	MAX_SEARCH_LIMIT     = 50
+)
+
+var SearchHandler *searchHandler
+
+type searchHandler struct {
+	searchService ports.SearchService
+}
+
+func (h *searchHandler) Initialize(searchService ports.SearchService) {
+	SearchHandler = &searchHandler{
+		searchService: searchService,
+	}
+}
+
+// Search handles GET /v1/search?q=...&k=...&chapter=...
+func (h *searchHandler) Search(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		helper.JSONResponse(c, http.StatusBadRequest, "q is required", nil)
+		return
+	}
+	opts := domain.SearchOptions{Limit: DEFAULT_SEARCH_LIMIT}
+	if k := c.Query("k"); k != "" {
+		limit, err := strconv.ParseUint(k, 10, 64)
+		if err != nil || limit == 0 || limit > MAX_SEARCH_LIMIT {
+			helper.JSONResponse(c, http.StatusBadRequest, fmt.Sprintf("k must be between 1 and %d", MAX_SEARCH_LIMIT), nil)
+			return
+		}
+		opts.Limit = limit
+	}
+	if chapter := c.Query("chapter"); chapter != "" {
+		number, err := strconv.ParseUint(chapter, 10, 64)
+		if err != nil || number == 0 || number > domain.CHAPTER_COUNT {
+			helper.JSONResponse(c, http.StatusBadRequest, fmt.Sprintf("chapter must be between 1 and %d", domain.CHAPTER_COUNT), nil)
+			return
+		}
+		opts.Chapter = number
+	}
+	hits, err := h.searchService.Search(c.Request.Context(), query, opts)
+	if err != nil {
+		fmt.Println("Error searching vectors:", err)
+		helper.JSONResponse(c, http.StatusBadGateway, "error searching the scripture", nil)
+		return
+	}
+	helper.JSONResponse(c, http.StatusOK, gin.H{
+		"query":   query,
+		"results": hits,
+	}, nil)
+}
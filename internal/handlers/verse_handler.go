@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+	"github.com/asifrahaman13/bhagabad_gita/internal/helper"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+var VerseHandler *verseHandler
+
+type verseHandler struct {
+	verseService         ports.VerseService
+	verseOfTheDayService ports.VerseOfTheDayService
+}
+
+func (h *verseHandler) Initialize(verseService ports.VerseService, verseOfTheDayService ports.VerseOfTheDayService) {
+	VerseHandler = &verseHandler{
+		verseService:         verseService,
+		verseOfTheDayService: verseOfTheDayService,
+	}
+}
+
+func (h *verseHandler) GetChapters(c *gin.Context) {
+	chapters, err := h.verseService.GetChapters(c.Request.Context())
+	if err != nil {
+		respondVerseError(c, err)
+		return
+	}
+	helper.JSONResponse(c, http.StatusOK, chapters, nil)
+}
+
+func (h *verseHandler) GetChapter(c *gin.Context) {
+	number, err := strconv.Atoi(c.Param("n"))
+	if err != nil || number <= 0 || number > domain.CHAPTER_COUNT {
+		helper.JSONResponse(c, http.StatusBadRequest, fmt.Sprintf("chapter must be between 1 and %d", domain.CHAPTER_COUNT), nil)
+		return
+	}
+	chapter, err := h.verseService.GetChapter(c.Request.Context(), number)
+	if err != nil {
+		respondVerseError(c, err)
+		return
+	}
+	helper.JSONResponse(c, http.StatusOK, chapter, nil)
+}
+
+// GetVerses serves /v1/verses/:chapter/:verse, where verse may be a range
+// within the chapter such as "47-50", and /v1/verses/:chapter, where chapter
+// holds a full reference such as "2.47" or "2.72-3.2".
+func (h *verseHandler) GetVerses(c *gin.Context) {
+	ref := c.Param("chapter")
+	if verse := c.Param("verse"); verse != "" {
+		ref = ref + "." + verse
+	}
+	verseRange, err := domain.ParseVerseRange(ref)
+	if err != nil {
+		helper.JSONResponse(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if verseRange.From == verseRange.To {
+		verse, err := h.verseService.GetVerse(c.Request.Context(), verseRange.From)
+		if err != nil {
+			respondVerseError(c, err)
+			return
+		}
+		helper.JSONResponse(c, http.StatusOK, verse, nil)
+		return
+	}
+	verses, err := h.verseService.GetVerseRange(c.Request.Context(), verseRange)
+	if err != nil {
+		respondVerseError(c, err)
+		return
+	}
+	helper.JSONResponse(c, http.StatusOK, gin.H{
+		"range":  verseRange,
+		"verses": verses,
+	}, nil)
+}
+
+func (h *verseHandler) GetVerseOfTheDay(c *gin.Context) {
+	verse, err := h.verseOfTheDayService.Today(c.Request.Context())
+	if err != nil {
+		respondVerseError(c, err)
+		return
+	}
+	helper.JSONResponse(c, http.StatusOK, verse, nil)
+}
+
+func respondVerseError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrNotFound) {
+		helper.JSONResponse(c, http.StatusNotFound, "not found", nil)
+		return
+	}
+	fmt.Println("Error reading verses:", err)
+	helper.JSONResponse(c, http.StatusInternalServerError, "error reading verses", nil)
+}
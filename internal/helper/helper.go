@@ -29,3 +29,18 @@ func JSONResponse(c *gin.Context, code int, message interface{}, body interface{
 		},
 	)
 }
+
+// CurrentUsername returns the username of the caller authenticated by
+// middleware.AuthMiddleware.
+func CurrentUsername(c *gin.Context) (string, bool) {
+	claims, exists := c.Get("username")
+	if !exists {
+		return "", false
+	}
+	mapClaims, ok := claims.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	username, ok := mapClaims["username"].(string)
+	return username, ok && username != ""
+}
@@ -0,0 +1,97 @@
+package helper
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+	"by": true, "for": true, "from": true, "how": true, "in": true, "is": true, "it": true,
+	"of": true, "on": true, "or": true, "that": true, "the": true, "this": true, "to": true,
+	"was": true, "what": true, "when": true, "where": true, "which": true, "who": true,
+	"why": true, "with": true, "does": true, "should": true, "can": true,
+}
+
+// Words splits text into lower-cased words.
+func Words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !isWordRune(r)
+	})
+}
+
+// QueryTerms splits a search query into lower-cased terms worth highlighting.
+func QueryTerms(query string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, word := range Words(query) {
+		if len([]rune(word)) < 3 || stopWords[word] || seen[word] {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+// isWordRune reports whether r belongs to a word. Combining marks count, so
+// that Devanagari vowel signs do not split a word apart.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+// atWordBoundaries reports whether content[start:end] is neither preceded nor
+// followed by a word rune. Regexp \b only knows ASCII words, so it cannot be
+// used for non-Latin scripts.
+func atWordBoundaries(content string, start, end int) bool {
+	if before, _ := utf8.DecodeLastRuneInString(content[:start]); start > 0 && isWordRune(before) {
+		return false
+	}
+	if after, _ := utf8.DecodeRuneInString(content[end:]); end < len(content) && isWordRune(after) {
+		return false
+	}
+	return true
+}
+
+// Highlight HTML-escapes content and wraps every whole-word, case-insensitive
+// occurrence of terms in <mark> tags. It also returns the terms that matched.
+func Highlight(content string, terms []string) (string, []string) {
+	if len(terms) == 0 {
+		return html.EscapeString(content), []string{}
+	}
+	sorted := append([]string(nil), terms...)
+	// Longer terms first so that "karma" wins over "kar" in the alternation.
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	quoted := make([]string, len(sorted))
+	for i, term := range sorted {
+		quoted[i] = regexp.QuoteMeta(term)
+	}
+	// Terms are matched against the raw content and the text around them is
+	// escaped separately, so a term never matches inside an entity like &amp;.
+	pattern := regexp.MustCompile(`(?i)` + strings.Join(quoted, "|"))
+	matched := make(map[string]bool)
+	var highlighted strings.Builder
+	last := 0
+	for _, loc := range pattern.FindAllStringIndex(content, -1) {
+		if !atWordBoundaries(content, loc[0], loc[1]) {
+			continue
+		}
+		match := content[loc[0]:loc[1]]
+		matched[strings.ToLower(match)] = true
+		highlighted.WriteString(html.EscapeString(content[last:loc[0]]))
+		highlighted.WriteString("<mark>" + html.EscapeString(match) + "</mark>")
+		last = loc[1]
+	}
+	highlighted.WriteString(html.EscapeString(content[last:]))
+	matchedTerms := []string{}
+	for _, term := range terms {
+		if matched[term] {
+			matchedTerms = append(matchedTerms, term)
+		}
+	}
+	return highlighted.String(), matchedTerms
+}
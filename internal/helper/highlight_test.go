@@ -0,0 +1,89 @@
+package helper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQueryTerms(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "What is Karma yoga?", want: []string{"karma", "yoga"}},
+		{query: "karma, KARMA and dharma", want: []string{"karma", "dharma"}},
+		{query: "verse 2.47", want: []string{"verse"}},
+		{query: "कर्म योग", want: []string{"कर्म", "योग"}},
+		{query: "om", want: nil},
+		{query: "the", want: nil},
+		{query: "", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := QueryTerms(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("QueryTerms(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		terms       []string
+		want        string
+		wantMatched []string
+	}{
+		{
+			name:        "whole words case-insensitively",
+			content:     "Karma is action; karmic bonds come from karma.",
+			terms:       []string{"karma"},
+			want:        "<mark>Karma</mark> is action; karmic bonds come from <mark>karma</mark>.",
+			wantMatched: []string{"karma"},
+		},
+		{
+			name:        "longer terms win and unmatched terms are left out",
+			content:     "The yogi practises yoga.",
+			terms:       []string{"yog", "yoga", "dharma"},
+			want:        "The yogi practises <mark>yoga</mark>.",
+			wantMatched: []string{"yoga"},
+		},
+		{
+			name:        "content is escaped",
+			content:     "<b>duty</b> & karma",
+			terms:       []string{"duty"},
+			want:        "&lt;b&gt;<mark>duty</mark>&lt;/b&gt; &amp; karma",
+			wantMatched: []string{"duty"},
+		},
+		{
+			name:        "terms never match inside entities",
+			content:     "R&amp;D",
+			terms:       []string{"amp"},
+			want:        "R&amp;<mark>amp</mark>;D",
+			wantMatched: []string{"amp"},
+		},
+		{
+			name:        "non-Latin words",
+			content:     "कर्मण्येवाधिकारस्ते, कर्म योग",
+			terms:       []string{"कर्म"},
+			want:        "कर्मण्येवाधिकारस्ते, <mark>कर्म</mark> योग",
+			wantMatched: []string{"कर्म"},
+		},
+		{
+			name:        "no terms",
+			content:     "a < b",
+			terms:       nil,
+			want:        "a &lt; b",
+			wantMatched: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, matched := Highlight(tt.content, tt.terms)
+			if got != tt.want || !reflect.DeepEqual(matched, tt.wantMatched) {
+				t.Errorf("Highlight() = %q, %q, want %q, %q", got, matched, tt.want, tt.wantMatched)
+			}
+		})
+	}
+}
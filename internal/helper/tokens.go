@@ -6,13 +6,23 @@ import (
 	jwt "github.com/dgrijalva/jwt-go"
 )
 
-var secretKey = []byte("SECRET_KEY")
+var (
+	secretKey []byte
+	tokenTTL  = 24 * time.Hour
+)
+
+// InitializeTokens sets the key tokens are signed with and how long they
+// stay valid. It must be called before tokens are created or verified.
+func InitializeTokens(secret string, ttl time.Duration) {
+	secretKey = []byte(secret)
+	tokenTTL = ttl
+}
 
 func CreateToken(username string, user_type string) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256,
 		jwt.MapClaims{
 			"username": username,
-			"exp":      time.Now().Add(time.Hour * 24).Unix(),
+			"exp":      time.Now().Add(tokenTTL).Unix(),
 			"user_type": user_type,
 		})
 	tokenString, err := token.SignedString(secretKey)
@@ -36,6 +46,5 @@ func VerifyToken(tokenString string) (map[string]interface{}, error) {
 	if !ok {
 		return nil, fmt.Errorf("invalid token claims")
 	}
-	fmt.Println("claims", claims)
 	return claims, nil
 }
@@ -11,4 +11,4 @@ func ConvertToSlice(a primitive.A) []string {
 		result[i] = fmt.Sprint(v)
 	}
 	return result
-}
\ No newline at end of file
+}
@@ -1,10 +1,82 @@
 package helper
 
 import (
-	"bytes"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
 	"strings"
+	"unicode"
 )
 
-func IsSentenceEnd(buffer bytes.Buffer) bool {
-	return strings.HasSuffix(buffer.String(), ".") || strings.HasSuffix(buffer.String(), "!") || strings.HasSuffix(buffer.String(), "?")
+// StreamChunker buffers streamed LLM output and releases it in pieces of the
+// requested granularity. Whatever is left when the stream ends is returned by Flush.
+type StreamChunker struct {
+	granularity string
+	buffer      strings.Builder
+}
+
+func NewStreamChunker(granularity string) *StreamChunker {
+	if granularity == "" {
+		granularity = domain.GranularitySentence
+	}
+	return &StreamChunker{granularity: granularity}
+}
+
+func (c *StreamChunker) Write(text string) []string {
+	if c.granularity == domain.GranularityToken {
+		if text == "" {
+			return nil
+		}
+		return []string{text}
+	}
+	c.buffer.WriteString(text)
+	var chunks []string
+	for {
+		content := c.buffer.String()
+		end := c.boundary(content)
+		if end <= 0 {
+			return chunks
+		}
+		chunks = append(chunks, content[:end])
+		c.buffer.Reset()
+		c.buffer.WriteString(content[end:])
+	}
+}
+
+func (c *StreamChunker) Flush() string {
+	rest := c.buffer.String()
+	c.buffer.Reset()
+	return rest
+}
+
+func (c *StreamChunker) boundary(content string) int {
+	if c.granularity == domain.GranularityParagraph {
+		if idx := strings.Index(content, "\n\n"); idx >= 0 {
+			return idx + 2
+		}
+		return -1
+	}
+	return sentenceBoundary(content)
+}
+
+// sentenceBoundary returns the index just past the first sentence ending in
+// content. A terminator only counts once the following character is known to
+// be whitespace, and a period right after a digit is never a boundary, so
+// verse references such as "2.47" and list markers such as "3." stay intact.
+func sentenceBoundary(content string) int {
+	runes := []rune(content)
+	offset := 0
+	for i, r := range runes {
+		offset += len(string(r))
+		if !IsSentenceTerminator(r) || i+1 >= len(runes) || !unicode.IsSpace(runes[i+1]) {
+			continue
+		}
+		if r == '.' && i > 0 && unicode.IsDigit(runes[i-1]) {
+			continue
+		}
+		return offset + len(string(runes[i+1]))
+	}
+	return -1
+}
+
+func IsSentenceTerminator(r rune) bool {
+	return r == '.' || r == '!' || r == '?'
 }
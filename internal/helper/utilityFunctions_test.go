@@ -0,0 +1,79 @@
+package helper
+
+import (
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"reflect"
+	"testing"
+)
+
+// stream writes the pieces through a chunker and returns every chunk it
+// released, followed by the flushed remainder when there is one.
+func stream(granularity string, pieces ...string) []string {
+	chunker := NewStreamChunker(granularity)
+	chunks := []string{}
+	for _, piece := range pieces {
+		chunks = append(chunks, chunker.Write(piece)...)
+	}
+	if rest := chunker.Flush(); rest != "" {
+		chunks = append(chunks, rest)
+	}
+	return chunks
+}
+
+func TestStreamChunker(t *testing.T) {
+	tests := []struct {
+		name        string
+		granularity string
+		pieces      []string
+		want        []string
+	}{
+		{
+			name:        "tokens pass through",
+			granularity: domain.GranularityToken,
+			pieces:      []string{"Act", "", " without", " attachment."},
+			want:        []string{"Act", " without", " attachment."},
+		},
+		{
+			name:        "sentences split after the following space",
+			granularity: domain.GranularitySentence,
+			pieces:      []string{"Do your duty", ". Do not ", "fear! Why", " worry? "},
+			want:        []string{"Do your duty. ", "Do not fear! ", "Why worry? "},
+		},
+		{
+			name:        "unterminated final sentence is flushed",
+			granularity: domain.GranularitySentence,
+			pieces:      []string{"The soul is eternal. It is never born"},
+			want:        []string{"The soul is eternal. ", "It is never born"},
+		},
+		{
+			name:        "verse references and list markers stay intact",
+			granularity: domain.GranularitySentence,
+			pieces:      []string{"See verse 2.", "47 first. Then:\n1. ", "act\n2. rest"},
+			want:        []string{"See verse 2.47 first. ", "Then:\n1. act\n2. rest"},
+		},
+		{
+			name:        "terminator at the end of a piece waits for the next",
+			granularity: domain.GranularitySentence,
+			pieces:      []string{"Arise.", " Fight."},
+			want:        []string{"Arise. ", "Fight."},
+		},
+		{
+			name:        "paragraphs split on blank lines",
+			granularity: domain.GranularityParagraph,
+			pieces:      []string{"First. Still first.\n", "\nSecond.\n\nThird"},
+			want:        []string{"First. Still first.\n\n", "Second.\n\n", "Third"},
+		},
+		{
+			name:   "sentence is the default",
+			pieces: []string{"One. Two."},
+			want:   []string{"One. ", "Two."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stream(tt.granularity, tt.pieces...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("chunks = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
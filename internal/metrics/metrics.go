@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"time"
+)
+
+const NAMESPACE = "bhagabad_gita"
+
+// Stages label ErrorsTotal with the step of the pipeline that failed.
+const (
+	STAGE_EMBEDDING     = "embedding"
+	STAGE_VECTOR_SEARCH = "vector_search"
+	STAGE_TRANSLATION   = "translation"
+	STAGE_GENERATION    = "generation"
+	STAGE_RECORD        = "record"
+)
+
+// Directions label WebsocketMessagesTotal.
+const (
+	DIRECTION_IN  = "in"
+	DIRECTION_OUT = "out"
+)
+
+// LATENCY_BUCKETS spans fast local calls up to slow model responses.
+var LATENCY_BUCKETS = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+var (
+	HTTPRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method and route.",
+		Buckets:   LATENCY_BUCKETS,
+	}, []string{"method", "route"})
+
+	WebsocketConnections = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: NAMESPACE,
+		Name:      "websocket_connections",
+		Help:      "Open websocket connections.",
+	})
+
+	WebsocketMessagesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "websocket_messages_total",
+		Help:      "Websocket messages by direction and message type.",
+	}, []string{"direction", "type"})
+
+	EmbeddingDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Name:      "embedding_duration_seconds",
+		Help:      "Time to embed a query.",
+		Buckets:   LATENCY_BUCKETS,
+	})
+
+	VectorSearchDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Name:      "vector_search_duration_seconds",
+		Help:      "Time to query Qdrant with an embedded query.",
+		Buckets:   LATENCY_BUCKETS,
+	})
+
+	RetrievalScore = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Name:      "retrieval_score",
+		Help:      "Similarity score of every passage returned by a vector search.",
+		Buckets:   prometheus.LinearBuckets(0.1, 0.1, 10),
+	})
+
+	LLMTimeToFirstToken = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Name:      "llm_time_to_first_token_seconds",
+		Help:      "Time from sending a streamed generate request to its first token.",
+		Buckets:   LATENCY_BUCKETS,
+	})
+
+	LLMTokensPerSecond = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Name:      "llm_tokens_per_second",
+		Help:      "Completion tokens per second of each streamed answer, as reported by the model.",
+		Buckets:   []float64{1, 2, 5, 10, 20, 30, 50, 75, 100, 150, 200},
+	})
+
+	LLMTokensTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "llm_tokens_total",
+		Help:      "Tokens processed by the model, by kind (prompt or completion).",
+	}, []string{"kind"})
+
+	ErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "errors_total",
+		Help:      "Failures by pipeline stage.",
+	}, []string{"stage"})
+)
+
+// Handler serves every registered metric, including the Go runtime and
+// process collectors, in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Since observes the seconds elapsed from start.
+func Since(observer prometheus.Observer, start time.Time) {
+	observer.Observe(time.Since(start).Seconds())
+}
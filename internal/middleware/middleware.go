@@ -1,16 +1,17 @@
 package middleware
 
 import (
-	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 	"github.com/asifrahaman13/bhagabad_gita/internal/helper"
+	"github.com/asifrahaman13/bhagabad_gita/internal/metrics"
 	"github.com/gin-gonic/gin"
 )
 
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		fmt.Println("Inside the Auth middleware", c.GetHeader("Authorization"))
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			helper.JSONResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
@@ -24,15 +25,58 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 		accessToken := parts[1]
-		fmt.Println("Access token: ", accessToken)
 		userName, err := helper.VerifyToken(accessToken)
 		if err != nil {
 			helper.JSONResponse(c, http.StatusUnauthorized, "Unauthorized", nil)
 			c.Abort() 
 			return
 		}
-		fmt.Println("User email: ", userName)
 		c.Set("username", userName)
 		c.Next()
 	}
 }
+
+// OptionalAuthMiddleware sets the username like AuthMiddleware when a valid
+// bearer token is sent, but lets anonymous requests through.
+func OptionalAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(parts) == 2 && parts[0] == "Bearer" {
+			if userName, err := helper.VerifyToken(parts[1]); err == nil {
+				c.Set("username", userName)
+			}
+		}
+		c.Next()
+	}
+}
+
+// AdminMiddleware must run after AuthMiddleware and only lets through
+// tokens issued with the "admin" user type.
+func AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, _ := c.Get("username")
+		mapClaims, ok := claims.(map[string]interface{})
+		if !ok || mapClaims["user_type"] != "admin" {
+			helper.JSONResponse(c, http.StatusForbidden, "Forbidden", nil)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// MetricsMiddleware counts requests and observes their latency by route
+// template, so /v1/chapters/1 and /v1/chapters/2 share a series. Requests
+// that match no route are grouped under "unmatched".
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.HTTPRequestsTotal.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		metrics.Since(metrics.HTTPRequestDuration.WithLabelValues(c.Request.Method, route), start)
+	}
+}
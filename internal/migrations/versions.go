@@ -0,0 +1,85 @@
+package migrations
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TOKENS_COLLECTION holds per-token documents such as revoked or refresh
+// tokens. They are removed by MongoDB once their expiresAt has passed.
+const TOKENS_COLLECTION = "tokens"
+
+// MIGRATIONS is the schema history. Append new migrations with the next
+// version; never edit or reorder one that has shipped.
+var MIGRATIONS = []Migration{
+	{
+		Version:     1,
+		Description: "unique usernames and emails",
+		Up: createIndexes("users",
+			index("users_username_unique", bson.D{{Key: "username", Value: 1}}, options.Index().SetUnique(true)),
+			// Email is optional, so only users that gave one are indexed.
+			index("users_email_unique", bson.D{{Key: "email", Value: 1}}, options.Index().SetUnique(true).SetPartialFilterExpression(
+				bson.D{{Key: "email", Value: bson.D{{Key: "$type", Value: "string"}, {Key: "$gt", Value: ""}}}},
+			)),
+		),
+		Down: dropIndexes("users", "users_username_unique", "users_email_unique"),
+	},
+	{
+		Version:     2,
+		Description: "expire tokens at expiresAt",
+		Up: createIndexes(TOKENS_COLLECTION,
+			index("tokens_expires_at_ttl", bson.D{{Key: "expiresAt", Value: 1}}, options.Index().SetExpireAfterSeconds(0)),
+		),
+		Down: dropIndexes(TOKENS_COLLECTION, "tokens_expires_at_ttl"),
+	},
+	{
+		Version:     3,
+		Description: "bookmark lookups and listing",
+		Up: createIndexes("bookmarks",
+			index("bookmarks_id_unique", bson.D{{Key: "id", Value: 1}}, options.Index().SetUnique(true)),
+			index("bookmarks_username_created_at", bson.D{{Key: "username", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: 1}}, nil),
+		),
+		Down: dropIndexes("bookmarks", "bookmarks_id_unique", "bookmarks_username_created_at"),
+	},
+	{
+		Version:     4,
+		Description: "answer lookups and history",
+		Up: createIndexes("answers",
+			index("answers_message_id_unique", bson.D{{Key: "messageId", Value: 1}}, options.Index().SetUnique(true)),
+			index("answers_username_created_at", bson.D{{Key: "username", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: 1}}, nil),
+		),
+		Down: dropIndexes("answers", "answers_message_id_unique", "answers_username_created_at"),
+	},
+	{
+		Version:     5,
+		Description: "one document per verse",
+		Up: createIndexes("verses",
+			index("verses_chapter_verse_unique", bson.D{{Key: "chapter", Value: 1}, {Key: "verse", Value: 1}}, options.Index().SetUnique(true)),
+		),
+		Down: dropIndexes("verses", "verses_chapter_verse_unique"),
+	},
+	{
+		Version:     6,
+		Description: "one document per chapter",
+		Up: createIndexes("chapters",
+			index("chapters_number_unique", bson.D{{Key: "number", Value: 1}}, options.Index().SetUnique(true)),
+		),
+		Down: dropIndexes("chapters", "chapters_number_unique"),
+	},
+	{
+		Version:     7,
+		Description: "one verse of the day per date",
+		Up: createIndexes("verse_of_the_day",
+			index("verse_of_the_day_date_unique", bson.D{{Key: "date", Value: 1}}, options.Index().SetUnique(true)),
+		),
+		Down: dropIndexes("verse_of_the_day", "verse_of_the_day_date_unique"),
+	},
+}
+
+func index(name string, keys bson.D, opts *options.IndexOptions) mongo.IndexModel {
+	if opts == nil {
+		opts = options.Index()
+	}
+	return mongo.IndexModel{Keys: keys, Options: opts.SetName(name)}
+}
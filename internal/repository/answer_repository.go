@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+)
+
+var AnswerRepo *AnswerRepository
+
+type AnswerRepository struct {
+	ports.BaseRepository[domain.AnswerRecord]
+}
+
+func (r *AnswerRepository) Initialize(store *Store) *AnswerRepository {
+	AnswerRepo = &AnswerRepository{
+		BaseRepository: newBaseRepository[domain.AnswerRecord](store),
+	}
+	return AnswerRepo
+}
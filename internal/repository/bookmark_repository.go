@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+)
+
+var BookmarkRepo *BookmarkRepository
+
+type BookmarkRepository struct {
+	ports.BaseRepository[domain.Bookmark]
+}
+
+func (r *BookmarkRepository) Initialize(store *Store) *BookmarkRepository {
+	BookmarkRepo = &BookmarkRepository{
+		BaseRepository: newBaseRepository[domain.Bookmark](store),
+	}
+	return BookmarkRepo
+}
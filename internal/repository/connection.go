@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/config"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"sync/atomic"
+	"time"
+)
+
+// MAX_RETRY_BACKOFF caps the wait between startup pings.
+const MAX_RETRY_BACKOFF = 30 * time.Second
+
+// Connection owns the process' MongoDB client. It is ready once the server
+// has answered a ping and stops being ready when a ping fails, shutdown
+// begins or the client is disconnected.
+type Connection struct {
+	client   *mongo.Client
+	conf     config.MongoConfig
+	ready    atomic.Bool
+	draining atomic.Bool
+}
+
+// Connect creates the client with the configured pool and timeouts and pings
+// the primary until it answers, backing off between attempts. It gives up
+// after conf.ConnectRetries attempts or when ctx is done.
+func Connect(ctx context.Context, conf config.MongoConfig) (*Connection, error) {
+	if conf.URI == "" {
+		return nil, errors.New("you must set your 'MONGODB_URI' environment variable")
+	}
+	clientOptions := options.Client().
+		ApplyURI(conf.URI).
+		SetMaxPoolSize(uint64(conf.MaxPoolSize)).
+		SetMinPoolSize(uint64(conf.MinPoolSize)).
+		SetMaxConnIdleTime(conf.MaxConnIdleTime).
+		SetConnectTimeout(conf.ConnectTimeout).
+		SetServerSelectionTimeout(conf.ConnectTimeout)
+	client, err := mongo.Connect(ctx, clientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to MongoDB: %w", err)
+	}
+	conn := &Connection{client: client, conf: conf}
+	backoff := conf.RetryBackoff
+	for attempt := int64(1); ; attempt++ {
+		err = conn.Ping(ctx)
+		if err == nil {
+			break
+		}
+		if attempt >= conf.ConnectRetries {
+			client.Disconnect(context.Background())
+			return nil, fmt.Errorf("MongoDB did not answer after %d attempts: %w", attempt, err)
+		}
+		fmt.Printf("MongoDB ping failed (attempt %d of %d), retrying in %s: %v\n", attempt, conf.ConnectRetries, backoff, err)
+		select {
+		case <-ctx.Done():
+			client.Disconnect(context.Background())
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, MAX_RETRY_BACKOFF)
+	}
+	fmt.Printf("Connected to MongoDB database %q\n", conf.Database)
+	return conn, nil
+}
+
+func (c *Connection) Client() *mongo.Client {
+	return c.client
+}
+
+// Database is the database all repositories and migrations work in.
+func (c *Connection) Database() *mongo.Database {
+	return c.client.Database(c.conf.Database)
+}
+
+// Ready reports whether the last ping succeeded and the connection is
+// neither draining nor disconnected.
+func (c *Connection) Ready() bool {
+	return c.ready.Load()
+}
+
+// Ping checks that the primary answers within the connect timeout and
+// updates Ready accordingly.
+func (c *Connection) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, c.conf.ConnectTimeout)
+	defer cancel()
+	err := c.client.Ping(ctx, readpref.Primary())
+	c.ready.Store(err == nil && !c.draining.Load())
+	return err
+}
+
+// Drain marks the connection not ready once shutdown begins. The client
+// stays usable for the operations still in flight until Disconnect.
+func (c *Connection) Drain() {
+	c.draining.Store(true)
+	c.ready.Store(false)
+}
+
+// Disconnect closes every pooled connection, waiting for in-flight
+// operations until ctx is done.
+func (c *Connection) Disconnect(ctx context.Context) error {
+	c.Drain()
+	return c.client.Disconnect(ctx)
+}
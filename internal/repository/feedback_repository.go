@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+)
+
+var FeedbackRepo *FeedbackRepository
+
+type FeedbackRepository struct {
+	ports.BaseRepository[domain.Feedback]
+}
+
+func (r *FeedbackRepository) Initialize(store *Store) *FeedbackRepository {
+	FeedbackRepo = &FeedbackRepository{
+		BaseRepository: newBaseRepository[domain.Feedback](store),
+	}
+	return FeedbackRepo
+}
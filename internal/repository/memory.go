@@ -0,0 +1,609 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// memoryUniqueKeys mirrors the unique indexes created by the migrations so
+// the memory backend rejects the same duplicates MongoDB would.
+var memoryUniqueKeys = map[string][]memoryIndex{
+	"users":                     {{fields: []string{"username"}}, {fields: []string{"email"}, partial: nonEmptyString("email")}},
+	"bookmarks":                 {{fields: []string{"id"}}},
+	"answers":                   {{fields: []string{"messageId"}}},
+	VERSES_COLLECTION:           {{fields: []string{"chapter", "verse"}}},
+	CHAPTERS_COLLECTION:         {{fields: []string{"number"}}},
+	VERSE_OF_THE_DAY_COLLECTION: {{fields: []string{"date"}}},
+}
+
+// memoryIndex is a unique index. When partial is set, only the documents it
+// accepts are indexed, like a partialFilterExpression.
+type memoryIndex struct {
+	fields  []string
+	partial func(document bson.Raw) bool
+}
+
+func nonEmptyString(field string) func(bson.Raw) bool {
+	return func(document bson.Raw) bool {
+		value, ok := lookup(document, field).StringValueOK()
+		return ok && value != ""
+	}
+}
+
+func (i memoryIndex) covers(document bson.Raw) bool {
+	return i.partial == nil || i.partial(document)
+}
+
+// memoryStore holds every collection as BSON documents in insertion order,
+// so models are encoded and decoded exactly as they would be by MongoDB.
+type memoryStore struct {
+	mu          sync.RWMutex
+	collections map[string][]bson.Raw
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{collections: make(map[string][]bson.Raw)}
+}
+
+type memoryRepository[T any] struct {
+	store *memoryStore
+}
+
+func (r *memoryRepository[T]) Create(ctx context.Context, model T, collection string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	document, err := toDocument(model, primitive.NewObjectID())
+	if err != nil {
+		return false, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	documents := r.store.collections[collection]
+	if err := checkUnique(collection, documents, document, -1); err != nil {
+		return false, err
+	}
+	r.store.collections[collection] = append(documents, document)
+	return true, nil
+}
+
+func (r *memoryRepository[T]) FindOne(ctx context.Context, filter ports.Filter, collection string) (T, error) {
+	var result T
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	index, err := r.store.first(filter, collection)
+	if err != nil {
+		return result, err
+	}
+	if index < 0 {
+		return result, domain.ErrNotFound
+	}
+	err = bson.Unmarshal(r.store.collections[collection][index], &result)
+	return result, err
+}
+
+func (r *memoryRepository[T]) Find(ctx context.Context, filter ports.Filter, opts ports.FindOptions, collection string) ([]T, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	documents, err := r.store.match(filter, collection)
+	r.store.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	sortDocuments(documents, opts.Sort)
+	if opts.Skip > 0 {
+		documents = documents[min(opts.Skip, int64(len(documents))):]
+	}
+	if opts.Limit > 0 && int64(len(documents)) > opts.Limit {
+		documents = documents[:opts.Limit]
+	}
+	return decodeAll[T](documents)
+}
+
+func (r *memoryRepository[T]) FindPage(ctx context.Context, filter ports.Filter, opts ports.PageOptions, collection string) (domain.Page[T], error) {
+	page := domain.Page[T]{Items: []T{}}
+	if err := ctx.Err(); err != nil {
+		return page, err
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = domain.DEFAULT_PAGE_LIMIT
+	}
+	order := append(append([]ports.SortField{}, opts.Sort...), ports.Asc("_id"))
+	sortKey := sortKey(order)
+	var after []bson.RawValue
+	if opts.Cursor != "" {
+		cursor, err := decodeCursor(opts.Cursor)
+		if err != nil || cursor.Sort != sortKey || len(cursor.Values) != len(opts.Sort) {
+			return page, domain.ErrInvalidCursor
+		}
+		for _, value := range append(cursor.Values, cursor.Id) {
+			raw, err := rawValue(value)
+			if err != nil {
+				return page, domain.ErrInvalidCursor
+			}
+			after = append(after, raw)
+		}
+	}
+	r.store.mu.RLock()
+	documents, err := r.store.match(filter, collection)
+	r.store.mu.RUnlock()
+	if err != nil {
+		return page, err
+	}
+	sortDocuments(documents, order)
+	if after != nil {
+		// Keep only the documents ordered after the cursor, the in-memory
+		// counterpart of keysetFilter.
+		start := sort.Search(len(documents), func(i int) bool {
+			return compareKeys(order, sortValues(documents[i], order), after) > 0
+		})
+		documents = documents[start:]
+	}
+	if int64(len(documents)) > limit {
+		documents = documents[:limit]
+		page.NextCursor, err = encodeCursor(sortKey, opts.Sort, documents[len(documents)-1])
+		if err != nil {
+			return page, err
+		}
+	}
+	if len(opts.Fields) > 0 {
+		for i, document := range documents {
+			if documents[i], err = project(document, opts.Fields, opts.Sort); err != nil {
+				return page, err
+			}
+		}
+	}
+	page.Items, err = decodeAll[T](documents)
+	return page, err
+}
+
+func (r *memoryRepository[T]) Count(ctx context.Context, filter ports.Filter, collection string) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	documents, err := r.store.match(filter, collection)
+	return int64(len(documents)), err
+}
+
+func (r *memoryRepository[T]) GetAll(ctx context.Context, collection string) ([]T, error) {
+	return r.Find(ctx, ports.Filter{}, ports.FindOptions{}, collection)
+}
+
+func (r *memoryRepository[T]) GetByField(ctx context.Context, field string, field_value string, collection string) (T, error) {
+	return r.FindOne(ctx, ports.Where(field, field_value), collection)
+}
+
+func (r *memoryRepository[T]) GetAllByField(ctx context.Context, field string, field_value string, collection string) ([]T, error) {
+	return r.Find(ctx, ports.Where(field, field_value), ports.FindOptions{}, collection)
+}
+
+func (r *memoryRepository[T]) Update(ctx context.Context, filter ports.Filter, model T, collection string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	index, err := r.store.first(filter, collection)
+	if err != nil || index < 0 {
+		return false, err
+	}
+	return true, r.store.replace(collection, index, model)
+}
+
+func (r *memoryRepository[T]) Upsert(ctx context.Context, filter ports.Filter, model T, collection string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	index, err := r.store.first(filter, collection)
+	if err != nil {
+		return false, err
+	}
+	if index >= 0 {
+		return false, r.store.replace(collection, index, model)
+	}
+	document, err := toDocument(model, primitive.NewObjectID())
+	if err != nil {
+		return false, err
+	}
+	documents := r.store.collections[collection]
+	if err := checkUnique(collection, documents, document, -1); err != nil {
+		return false, err
+	}
+	r.store.collections[collection] = append(documents, document)
+	return true, nil
+}
+
+func (r *memoryRepository[T]) SetFields(ctx context.Context, filter ports.Filter, fields map[string]interface{}, collection string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	index, err := r.store.first(filter, collection)
+	if err != nil || index < 0 {
+		return false, err
+	}
+	var document bson.D
+	if err := bson.Unmarshal(r.store.collections[collection][index], &document); err != nil {
+		return false, err
+	}
+	for field, value := range fields {
+		i := slices.IndexFunc(document, func(element bson.E) bool { return element.Key == field })
+		if i < 0 {
+			document = append(document, bson.E{Key: field, Value: value})
+		} else {
+			document[i].Value = value
+		}
+	}
+	return true, r.store.replace(collection, index, document)
+}
+
+func (r *memoryRepository[T]) Delete(ctx context.Context, filter ports.Filter, collection string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	index, err := r.store.first(filter, collection)
+	if err != nil || index < 0 {
+		return false, err
+	}
+	documents := r.store.collections[collection]
+	r.store.collections[collection] = append(documents[:index:index], documents[index+1:]...)
+	return true, nil
+}
+
+func (r *memoryRepository[T]) UpdateByField(ctx context.Context, field string, field_value string, model T, collection string) (bool, error) {
+	return r.Update(ctx, ports.Where(field, field_value), model, collection)
+}
+
+func (r *memoryRepository[T]) DeleteByField(ctx context.Context, field string, field_value string, collection string) (bool, error) {
+	return r.Delete(ctx, ports.Where(field, field_value), collection)
+}
+
+// first returns the index of the first document matching filter, or -1.
+// Callers must hold the lock.
+func (s *memoryStore) first(filter ports.Filter, collection string) (int, error) {
+	for i, document := range s.collections[collection] {
+		matched, err := matches(document, filter)
+		if err != nil {
+			return -1, err
+		}
+		if matched {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+// match returns the documents matching filter in insertion order. Callers
+// must hold the lock.
+func (s *memoryStore) match(filter ports.Filter, collection string) ([]bson.Raw, error) {
+	documents := []bson.Raw{}
+	for _, document := range s.collections[collection] {
+		matched, err := matches(document, filter)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			documents = append(documents, document)
+		}
+	}
+	return documents, nil
+}
+
+// replace swaps the document at index for model, keeping its _id like
+// ReplaceOne does. Callers must hold the write lock.
+func (s *memoryStore) replace(collection string, index int, model interface{}) error {
+	documents := s.collections[collection]
+	document, err := toDocument(model, documents[index].Lookup("_id"))
+	if err != nil {
+		return err
+	}
+	if err := checkUnique(collection, documents, document, index); err != nil {
+		return err
+	}
+	documents[index] = document
+	return nil
+}
+
+// toDocument encodes model and sets its _id to id unless the model has one.
+func toDocument(model interface{}, id interface{}) (bson.Raw, error) {
+	data, err := bson.Marshal(model)
+	if err != nil {
+		return nil, err
+	}
+	var document bson.D
+	if err := bson.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	for _, element := range document {
+		if element.Key == "_id" {
+			return data, nil
+		}
+	}
+	return bson.Marshal(append(bson.D{{Key: "_id", Value: id}}, document...))
+}
+
+// checkUnique reports domain.ErrDuplicate when document shares a unique key
+// with any document other than the one at skip.
+func checkUnique(collection string, documents []bson.Raw, document bson.Raw, skip int) error {
+	for _, index := range memoryUniqueKeys[collection] {
+		if !index.covers(document) {
+			continue
+		}
+		key := uniqueKey(document, index.fields)
+		for i, existing := range documents {
+			if i != skip && index.covers(existing) && uniqueKey(existing, index.fields) == key {
+				return fmt.Errorf("%w: %s already has a document with this %s", domain.ErrDuplicate, collection, strings.Join(index.fields, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// uniqueKey treats missing fields as null, as a non-sparse unique index does.
+func uniqueKey(document bson.Raw, fields []string) string {
+	var key strings.Builder
+	for _, field := range fields {
+		value := lookup(document, field)
+		if value.Type == bsontype.Type(0) {
+			value = bson.RawValue{Type: bsontype.Null}
+		}
+		fmt.Fprintf(&key, "%d:%x;", value.Type, value.Value)
+	}
+	return key.String()
+}
+
+func decodeAll[T any](documents []bson.Raw) ([]T, error) {
+	results := make([]T, 0, len(documents))
+	for _, document := range documents {
+		var item T
+		if err := bson.Unmarshal(document, &item); err != nil {
+			return nil, err
+		}
+		results = append(results, item)
+	}
+	return results, nil
+}
+
+// project keeps _id, the requested fields and the sort fields, matching the
+// projection FindPage sends to MongoDB.
+func project(document bson.Raw, fields []string, sort []ports.SortField) (bson.Raw, error) {
+	included := map[string]bool{"_id": true}
+	for _, field := range projection(fields, sort) {
+		included[strings.Split(field.Key, ".")[0]] = true
+	}
+	var full, projected bson.D
+	if err := bson.Unmarshal(document, &full); err != nil {
+		return nil, err
+	}
+	for _, element := range full {
+		if included[element.Key] {
+			projected = append(projected, element)
+		}
+	}
+	return bson.Marshal(projected)
+}
+
+func lookup(document bson.Raw, field string) bson.RawValue {
+	value, err := document.LookupErr(strings.Split(field, ".")...)
+	if err != nil {
+		return bson.RawValue{}
+	}
+	return value
+}
+
+func rawValue(value interface{}) (bson.RawValue, error) {
+	if raw, ok := value.(bson.RawValue); ok {
+		return raw, nil
+	}
+	if value == nil {
+		return bson.RawValue{Type: bsontype.Null}, nil
+	}
+	kind, data, err := bson.MarshalValue(value)
+	if err != nil {
+		return bson.RawValue{}, err
+	}
+	return bson.RawValue{Type: kind, Value: data}, nil
+}
+
+// matches evaluates filter with MongoDB's semantics for the operators the
+// Filter builder supports: a missing field compares as null, and a condition
+// on an array field matches when any element does.
+func matches(document bson.Raw, filter ports.Filter) (bool, error) {
+	for _, condition := range filter.Conditions {
+		want, err := rawValue(condition.Value)
+		if err != nil {
+			return false, err
+		}
+		if !matchesCondition(lookup(document, condition.Field), condition.Operator, want) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func matchesCondition(value bson.RawValue, operator ports.Operator, want bson.RawValue) bool {
+	if operator == ports.OpNe {
+		return !matchesCondition(value, ports.OpEq, want)
+	}
+	candidates := []bson.RawValue{value}
+	if value.Type == bsontype.Array {
+		if elements, err := value.Array().Values(); err == nil {
+			candidates = append(candidates, elements...)
+		}
+	}
+	for _, candidate := range candidates {
+		if matchesValue(candidate, operator, want) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesValue(value bson.RawValue, operator ports.Operator, want bson.RawValue) bool {
+	if operator == ports.OpIn {
+		options, err := want.Array().Values()
+		if err != nil {
+			return false
+		}
+		for _, option := range options {
+			if matchesValue(value, ports.OpEq, option) {
+				return true
+			}
+		}
+		return false
+	}
+	// Comparisons never cross types, so {verse: {$gt: 2}} skips strings.
+	if typeRank(value) != typeRank(want) {
+		return false
+	}
+	order := compareValues(value, want)
+	switch operator {
+	case ports.OpEq:
+		return order == 0
+	case ports.OpGt:
+		return order > 0
+	case ports.OpGte:
+		return order >= 0
+	case ports.OpLt:
+		return order < 0
+	case ports.OpLte:
+		return order <= 0
+	}
+	return false
+}
+
+// typeRank orders BSON types the way MongoDB does when sorting mixed values.
+func typeRank(value bson.RawValue) int {
+	switch value.Type {
+	case bsontype.Type(0), bsontype.Null, bsontype.Undefined:
+		return 1
+	case bsontype.Double, bsontype.Int32, bsontype.Int64, bsontype.Decimal128:
+		return 2
+	case bsontype.String, bsontype.Symbol:
+		return 3
+	case bsontype.EmbeddedDocument:
+		return 4
+	case bsontype.Array:
+		return 5
+	case bsontype.Binary:
+		return 6
+	case bsontype.ObjectID:
+		return 7
+	case bsontype.Boolean:
+		return 8
+	case bsontype.DateTime:
+		return 9
+	case bsontype.Timestamp:
+		return 10
+	}
+	return 11
+}
+
+func compareValues(a, b bson.RawValue) int {
+	if rankA, rankB := typeRank(a), typeRank(b); rankA != rankB {
+		return rankA - rankB
+	}
+	switch typeRank(a) {
+	case 1:
+		return 0
+	case 2:
+		x, y := number(a), number(b)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	case 3:
+		return strings.Compare(a.StringValue(), b.StringValue())
+	case 7:
+		x, y := a.ObjectID(), b.ObjectID()
+		return bytes.Compare(x[:], y[:])
+	case 8:
+		x, y := a.Boolean(), b.Boolean()
+		switch {
+		case x == y:
+			return 0
+		case y:
+			return -1
+		}
+		return 1
+	case 9:
+		x, y := a.DateTime(), b.DateTime()
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+	return bytes.Compare(a.Value, b.Value)
+}
+
+func number(value bson.RawValue) float64 {
+	switch value.Type {
+	case bsontype.Int32:
+		return float64(value.Int32())
+	case bsontype.Int64:
+		return float64(value.Int64())
+	case bsontype.Decimal128:
+		number, _ := strconv.ParseFloat(value.Decimal128().String(), 64)
+		return number
+	}
+	return value.Double()
+}
+
+func sortValues(document bson.Raw, order []ports.SortField) []bson.RawValue {
+	values := make([]bson.RawValue, 0, len(order))
+	for _, field := range order {
+		values = append(values, lookup(document, field.Field))
+	}
+	return values
+}
+
+func compareKeys(order []ports.SortField, a, b []bson.RawValue) int {
+	for i, field := range order {
+		if result := compareValues(a[i], b[i]); result != 0 {
+			if field.Descending {
+				return -result
+			}
+			return result
+		}
+	}
+	return 0
+}
+
+func sortDocuments(documents []bson.Raw, order []ports.SortField) {
+	if len(order) == 0 {
+		return
+	}
+	sort.SliceStable(documents, func(i, j int) bool {
+		return compareKeys(order, sortValues(documents[i], order), sortValues(documents[j], order)) < 0
+	})
+}
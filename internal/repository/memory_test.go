@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+	"reflect"
+	"testing"
+)
+
+type memoryDoc struct {
+	Name string      `bson:"name"`
+	Rank interface{} `bson:"rank,omitempty"`
+	Tags []string    `bson:"tags,omitempty"`
+}
+
+func names(docs []memoryDoc) []string {
+	result := []string{}
+	for _, doc := range docs {
+		result = append(result, doc.Name)
+	}
+	return result
+}
+
+func seed(t *testing.T, repo ports.BaseRepository[memoryDoc], docs ...memoryDoc) {
+	t.Helper()
+	for _, doc := range docs {
+		if _, err := repo.Create(context.Background(), doc, "docs"); err != nil {
+			t.Fatalf("Create(%s): %v", doc.Name, err)
+		}
+	}
+}
+
+func TestMemoryFind(t *testing.T) {
+	repo := newBaseRepository[memoryDoc](NewMemoryStore())
+	seed(t, repo,
+		memoryDoc{Name: "a", Rank: 3, Tags: []string{"duty", "action"}},
+		memoryDoc{Name: "b", Rank: "3"},
+		memoryDoc{Name: "c", Rank: 1, Tags: []string{"devotion"}},
+		memoryDoc{Name: "d"},
+		memoryDoc{Name: "e", Rank: 2.5, Tags: []string{"duty"}},
+	)
+	tests := []struct {
+		name   string
+		filter ports.Filter
+		opts   ports.FindOptions
+		want   []string
+	}{
+		{name: "zero filter", want: []string{"a", "b", "c", "d", "e"}},
+		{name: "array element", filter: ports.Where("tags", "duty"), want: []string{"a", "e"}},
+		{name: "comparisons do not cross types", filter: ports.Filter{}.AndOp("rank", ports.OpGt, 2), want: []string{"a", "e"}},
+		{name: "string comparison", filter: ports.Filter{}.AndOp("rank", ports.OpGte, "3"), want: []string{"b"}},
+		{name: "missing field equals null", filter: ports.Where("rank", nil), want: []string{"d"}},
+		{name: "not equal includes missing fields", filter: ports.Filter{}.AndOp("tags", ports.OpNe, "duty"), want: []string{"b", "c", "d"}},
+		{name: "in", filter: ports.Filter{}.AndOp("name", ports.OpIn, []string{"c", "e", "z"}), want: []string{"c", "e"}},
+		{
+			name:   "range on one field",
+			filter: ports.Filter{}.AndOp("rank", ports.OpGt, 1).AndOp("rank", ports.OpLt, 3),
+			want:   []string{"e"},
+		},
+		{
+			// Missing fields sort first, then numbers before strings.
+			name: "sort across types",
+			opts: ports.FindOptions{Sort: []ports.SortField{ports.Asc("rank")}},
+			want: []string{"d", "c", "e", "a", "b"},
+		},
+		{
+			name: "skip and limit",
+			opts: ports.FindOptions{Sort: []ports.SortField{ports.Desc("name")}, Skip: 1, Limit: 2},
+			want: []string{"d", "c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.Find(context.Background(), tt.filter, tt.opts, "docs")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(names(got), tt.want) {
+				t.Errorf("Find() = %v, want %v", names(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryFindPage(t *testing.T) {
+	repo := newBaseRepository[memoryDoc](NewMemoryStore())
+	seed(t, repo,
+		memoryDoc{Name: "a", Rank: 2},
+		memoryDoc{Name: "b", Rank: 3},
+		memoryDoc{Name: "c", Rank: 2},
+		memoryDoc{Name: "d", Rank: 1},
+		memoryDoc{Name: "e", Rank: 3},
+	)
+	opts := ports.PageOptions{Sort: []ports.SortField{ports.Desc("rank")}, Limit: 2}
+	var pages [][]string
+	for {
+		page, err := repo.FindPage(context.Background(), ports.Filter{}, opts, "docs")
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages = append(pages, names(page.Items))
+		if page.NextCursor == "" {
+			break
+		}
+		opts.Cursor = page.NextCursor
+	}
+	// Ties on rank are broken by _id, which follows insertion order.
+	want := [][]string{{"b", "e"}, {"a", "c"}, {"d"}}
+	if !reflect.DeepEqual(pages, want) {
+		t.Errorf("pages = %v, want %v", pages, want)
+	}
+
+	first, err := repo.FindPage(context.Background(), ports.Filter{}, ports.PageOptions{Sort: opts.Sort, Limit: 2}, "docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := ports.PageOptions{Sort: []ports.SortField{ports.Asc("name")}, Cursor: first.NextCursor}
+	if _, err := repo.FindPage(context.Background(), ports.Filter{}, other, "docs"); !errors.Is(err, domain.ErrInvalidCursor) {
+		t.Errorf("cursor reused with another sort: err = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestMemoryUniqueIndexes(t *testing.T) {
+	ctx := context.Background()
+	repo := newBaseRepository[domain.User](NewMemoryStore())
+	create := func(user domain.User) error {
+		_, err := repo.Create(ctx, user, "users")
+		return err
+	}
+	if err := create(domain.User{Username: "arjuna", Email: "arjuna@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := create(domain.User{Username: "arjuna", Email: "other@example.com"}); !errors.Is(err, domain.ErrDuplicate) {
+		t.Errorf("duplicate username: err = %v, want ErrDuplicate", err)
+	}
+	if err := create(domain.User{Username: "bhima", Email: "arjuna@example.com"}); !errors.Is(err, domain.ErrDuplicate) {
+		t.Errorf("duplicate email: err = %v, want ErrDuplicate", err)
+	}
+	// The email index is partial, so any number of users may have no email.
+	if err := create(domain.User{Username: "bhima"}); err != nil {
+		t.Errorf("first user without email: %v", err)
+	}
+	if err := create(domain.User{Username: "nakula"}); err != nil {
+		t.Errorf("second user without email: %v", err)
+	}
+
+	if _, err := repo.SetFields(ctx, ports.Where("username", "bhima"), map[string]interface{}{"email": "arjuna@example.com"}, "users"); !errors.Is(err, domain.ErrDuplicate) {
+		t.Errorf("SetFields to a taken email: err = %v, want ErrDuplicate", err)
+	}
+	updated, err := repo.SetFields(ctx, ports.Where("username", "bhima"), map[string]interface{}{"email": "bhima@example.com"}, "users")
+	if err != nil || !updated {
+		t.Fatalf("SetFields() = %v, %v", updated, err)
+	}
+	user, err := repo.FindOne(ctx, ports.Where("email", "bhima@example.com"), "users")
+	if err != nil || user.Username != "bhima" {
+		t.Errorf("FindOne by new email = %+v, %v", user, err)
+	}
+	if updated, err := repo.SetFields(ctx, ports.Where("username", "karna"), map[string]interface{}{"email": "x@example.com"}, "users"); err != nil || updated {
+		t.Errorf("SetFields on a missing user = %v, %v, want false, nil", updated, err)
+	}
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"strings"
+)
+
+// pageCursor is the decoded form of an opaque page cursor: the sort order it
+// was issued for and the sort values and _id of the last item returned.
+type pageCursor struct {
+	Sort   string      `bson:"s"`
+	Values bson.A      `bson:"v"`
+	Id     interface{} `bson:"i"`
+}
+
+func (r *repository[T]) FindPage(ctx context.Context, filter ports.Filter, opts ports.PageOptions, collection string) (domain.Page[T], error) {
+	coll := r.collection(collection)
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	page := domain.Page[T]{Items: []T{}}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = domain.DEFAULT_PAGE_LIMIT
+	}
+	order := append(append([]ports.SortField{}, opts.Sort...), ports.Asc("_id"))
+	sortKey := sortKey(order)
+	query := filterToBSON(filter)
+	if opts.Cursor != "" {
+		cursor, err := decodeCursor(opts.Cursor)
+		if err != nil || cursor.Sort != sortKey || len(cursor.Values) != len(opts.Sort) {
+			return page, domain.ErrInvalidCursor
+		}
+		after := keysetFilter(order, append(cursor.Values, cursor.Id))
+		if len(query) == 0 {
+			query = after
+		} else {
+			query = bson.D{{Key: "$and", Value: bson.A{query, after}}}
+		}
+	}
+	findOptions := options.Find().SetSort(sortToBSON(order)).SetLimit(limit + 1)
+	if len(opts.Fields) > 0 {
+		findOptions.SetProjection(projection(opts.Fields, opts.Sort))
+	}
+	results, err := coll.Find(ctx, query, findOptions)
+	if err != nil {
+		return page, err
+	}
+	var documents []bson.Raw
+	if err := results.All(ctx, &documents); err != nil {
+		return page, err
+	}
+	if int64(len(documents)) > limit {
+		documents = documents[:limit]
+		page.NextCursor, err = encodeCursor(sortKey, opts.Sort, documents[len(documents)-1])
+		if err != nil {
+			return page, err
+		}
+	}
+	for _, document := range documents {
+		var item T
+		if err := bson.Unmarshal(document, &item); err != nil {
+			return page, err
+		}
+		page.Items = append(page.Items, item)
+	}
+	return page, nil
+}
+
+// keysetFilter matches the documents ordered after values, e.g. for a sort
+// on (createdAt desc, _id asc):
+//
+//	{$or: [{createdAt: {$lt: c}}, {createdAt: c, _id: {$gt: id}}]}
+func keysetFilter(order []ports.SortField, values bson.A) bson.D {
+	clauses := bson.A{}
+	for i, field := range order {
+		clause := bson.D{}
+		for j := 0; j < i; j++ {
+			clause = append(clause, bson.E{Key: order[j].Field, Value: values[j]})
+		}
+		operator := "$gt"
+		if field.Descending {
+			operator = "$lt"
+		}
+		clause = append(clause, bson.E{Key: field.Field, Value: bson.D{{Key: operator, Value: values[i]}}})
+		clauses = append(clauses, clause)
+	}
+	return bson.D{{Key: "$or", Value: clauses}}
+}
+
+// projection always includes the sort fields, which the next cursor is
+// built from.
+func projection(fields []string, sort []ports.SortField) bson.D {
+	included := bson.D{}
+	seen := make(map[string]bool)
+	for _, field := range fields {
+		if !seen[field] {
+			seen[field] = true
+			included = append(included, bson.E{Key: field, Value: 1})
+		}
+	}
+	for _, field := range sort {
+		if !seen[field.Field] {
+			seen[field.Field] = true
+			included = append(included, bson.E{Key: field.Field, Value: 1})
+		}
+	}
+	return included
+}
+
+func sortKey(order []ports.SortField) string {
+	keys := make([]string, 0, len(order))
+	for _, field := range order {
+		key := field.Field
+		if field.Descending {
+			key = "-" + key
+		}
+		keys = append(keys, key)
+	}
+	return strings.Join(keys, ",")
+}
+
+func encodeCursor(sortKey string, sort []ports.SortField, last bson.Raw) (string, error) {
+	cursor := pageCursor{Sort: sortKey, Values: bson.A{}}
+	for _, field := range sort {
+		value, err := last.LookupErr(strings.Split(field.Field, ".")...)
+		if err != nil {
+			cursor.Values = append(cursor.Values, nil)
+			continue
+		}
+		cursor.Values = append(cursor.Values, value)
+	}
+	cursor.Id = last.Lookup("_id")
+	data, err := bson.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(encoded string) (pageCursor, error) {
+	var cursor pageCursor
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return cursor, err
+	}
+	err = bson.Unmarshal(data, &cursor)
+	return cursor, err
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"reflect"
+	"testing"
+)
+
+func TestKeysetFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		order  []ports.SortField
+		values bson.A
+		want   bson.D
+	}{
+		{
+			name:   "id only",
+			order:  []ports.SortField{ports.Asc("_id")},
+			values: bson.A{"b1"},
+			want: bson.D{{Key: "$or", Value: bson.A{
+				bson.D{{Key: "_id", Value: bson.D{{Key: "$gt", Value: "b1"}}}},
+			}}},
+		},
+		{
+			name:   "descending field with id tiebreak",
+			order:  []ports.SortField{ports.Desc("createdAt"), ports.Asc("_id")},
+			values: bson.A{int64(100), "b1"},
+			want: bson.D{{Key: "$or", Value: bson.A{
+				bson.D{{Key: "createdAt", Value: bson.D{{Key: "$lt", Value: int64(100)}}}},
+				bson.D{
+					{Key: "createdAt", Value: int64(100)},
+					{Key: "_id", Value: bson.D{{Key: "$gt", Value: "b1"}}},
+				},
+			}}},
+		},
+		{
+			name:   "two sort fields",
+			order:  []ports.SortField{ports.Asc("chapter"), ports.Desc("verse"), ports.Asc("_id")},
+			values: bson.A{2, 47, "b1"},
+			want: bson.D{{Key: "$or", Value: bson.A{
+				bson.D{{Key: "chapter", Value: bson.D{{Key: "$gt", Value: 2}}}},
+				bson.D{
+					{Key: "chapter", Value: 2},
+					{Key: "verse", Value: bson.D{{Key: "$lt", Value: 47}}},
+				},
+				bson.D{
+					{Key: "chapter", Value: 2},
+					{Key: "verse", Value: 47},
+					{Key: "_id", Value: bson.D{{Key: "$gt", Value: "b1"}}},
+				},
+			}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := keysetFilter(tt.order, tt.values); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("keysetFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	sort := []ports.SortField{ports.Desc("createdAt"), ports.Asc("meta.rank")}
+	key := sortKey(append(append([]ports.SortField{}, sort...), ports.Asc("_id")))
+	if key != "-createdAt,meta.rank,_id" {
+		t.Fatalf("sortKey() = %q", key)
+	}
+	last, err := bson.Marshal(bson.D{
+		{Key: "_id", Value: "b1"},
+		{Key: "createdAt", Value: int64(100)},
+		{Key: "meta", Value: bson.D{{Key: "rank", Value: int32(3)}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := encodeCursor(key, sort, last)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cursor, err := decodeCursor(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cursor.Sort != key || !reflect.DeepEqual(cursor.Values, bson.A{int64(100), int32(3)}) || cursor.Id != "b1" {
+		t.Errorf("decodeCursor() = %+v", cursor)
+	}
+	if _, err := decodeCursor("not a cursor"); err == nil {
+		t.Error("decodeCursor accepted a malformed cursor")
+	}
+}
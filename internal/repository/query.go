@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// filterToBSON translates a ports.Filter into a MongoDB query. Conditions on
+// the same field are merged into one operator document.
+func filterToBSON(filter ports.Filter) bson.D {
+	query := bson.D{}
+	fields := make(map[string]int)
+	for _, condition := range filter.Conditions {
+		operator := bson.E{Key: string(condition.Operator), Value: condition.Value}
+		if index, exists := fields[condition.Field]; exists {
+			query[index].Value = append(query[index].Value.(bson.D), operator)
+			continue
+		}
+		fields[condition.Field] = len(query)
+		query = append(query, bson.E{Key: condition.Field, Value: bson.D{operator}})
+	}
+	return query
+}
+
+func sortToBSON(fields []ports.SortField) bson.D {
+	sort := bson.D{}
+	for _, field := range fields {
+		direction := 1
+		if field.Descending {
+			direction = -1
+		}
+		sort = append(sort, bson.E{Key: field.Field, Value: direction})
+	}
+	return sort
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+	"go.mongodb.org/mongo-driver/bson"
+	"reflect"
+	"testing"
+)
+
+func TestFilterToBSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter ports.Filter
+		want   bson.D
+	}{
+		{
+			name:   "zero filter matches everything",
+			filter: ports.Filter{},
+			want:   bson.D{},
+		},
+		{
+			name:   "equality conditions",
+			filter: ports.Where("username", "arjuna").And("tags", "duty"),
+			want: bson.D{
+				{Key: "username", Value: bson.D{{Key: "$eq", Value: "arjuna"}}},
+				{Key: "tags", Value: bson.D{{Key: "$eq", Value: "duty"}}},
+			},
+		},
+		{
+			name: "conditions on the same field are merged",
+			filter: ports.Where("username", "arjuna").
+				AndOp("chapter", ports.OpGte, 2).
+				AndOp("chapter", ports.OpLt, 5),
+			want: bson.D{
+				{Key: "username", Value: bson.D{{Key: "$eq", Value: "arjuna"}}},
+				{Key: "chapter", Value: bson.D{{Key: "$gte", Value: 2}, {Key: "$lt", Value: 5}}},
+			},
+		},
+		{
+			name:   "in",
+			filter: ports.Filter{}.AndOp("chapter", ports.OpIn, []int{2, 3}),
+			want:   bson.D{{Key: "chapter", Value: bson.D{{Key: "$in", Value: []int{2, 3}}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filterToBSON(tt.filter); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterToBSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterToBSONDoesNotShareConditions(t *testing.T) {
+	base := ports.Where("username", "arjuna")
+	first := base.And("tags", "duty")
+	second := base.And("tags", "devotion")
+	if got := filterToBSON(first)[1].Value; !reflect.DeepEqual(got, bson.D{{Key: "$eq", Value: "duty"}}) {
+		t.Errorf("first filter tags = %v, want duty", got)
+	}
+	if got := filterToBSON(second)[1].Value; !reflect.DeepEqual(got, bson.D{{Key: "$eq", Value: "devotion"}}) {
+		t.Errorf("second filter tags = %v, want devotion", got)
+	}
+}
+
+func TestSortToBSON(t *testing.T) {
+	got := sortToBSON([]ports.SortField{ports.Desc("createdAt"), ports.Asc("_id")})
+	want := bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sortToBSON() = %v, want %v", got, want)
+	}
+}
@@ -4,116 +4,185 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
-	"os"
-	"github.com/joho/godotenv"
+	"github.com/asifrahaman13/bhagabad_gita/internal/config"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+	"time"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type repository[T any] struct {
-	db *mongo.Client
+	db       *mongo.Client
+	database string
+	timeout  time.Duration
 }
 
-func InitializeDB() (*mongo.Client, error) {
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file is specified.")
+func newRepository[T any](db *mongo.Client, conf config.MongoConfig) *repository[T] {
+	return &repository[T]{
+		db:       db,
+		database: conf.Database,
+		timeout:  conf.OperationTimeout,
 	}
-	uri := os.Getenv("MONGODB_URI")
-	if uri == "" {
-		return nil, errors.New("you must set your 'MONGODB_URI' environment variable")
-	}
-	client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI(uri))
-	if err != nil {
-		return nil, fmt.Errorf("error connecting to MongoDB: %s", err)
+}
+
+func (r *repository[T]) collection(name string) *mongo.Collection {
+	return r.db.Database(r.database).Collection(name)
+}
+
+// withTimeout bounds a single operation so a slow MongoDB cannot hold the
+// caller beyond the configured operation timeout.
+func (r *repository[T]) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.timeout <= 0 {
+		return context.WithCancel(ctx)
 	}
-	fmt.Println("Connected to MongoDB!")
-	return client, nil
+	return context.WithTimeout(ctx, r.timeout)
 }
 
-func (r *repository[T]) Create(model T, collection string) (bool, error) {
-	coll := r.db.Database("bhagabad_gita").Collection(collection)
-	_, err := coll.InsertOne(context.TODO(), model)
+func (r *repository[T]) Create(ctx context.Context, model T, collection string) (bool, error) {
+	coll := r.collection(collection)
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	_, err := coll.InsertOne(ctx, model)
 	if err != nil {
-		return false, err
+		return false, duplicate(err)
 	}
 	return true, nil
 }
 
-func (r *repository[T]) GetByField(field string, field_value string, collection string) (interface{}, error) {
-	coll := r.db.Database("bhagabad_gita").Collection(collection)
-	filter := bson.D{{Key: field, Value: field_value}}
-	var result map[string]interface{}
-	err := coll.FindOne(context.TODO(), filter).Decode(&result)
+func (r *repository[T]) FindOne(ctx context.Context, filter ports.Filter, collection string) (T, error) {
+	coll := r.collection(collection)
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	var result T
+	err := coll.FindOne(ctx, filterToBSON(filter)).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return result, domain.ErrNotFound
+	}
+	return result, err
+}
+
+func (r *repository[T]) Find(ctx context.Context, filter ports.Filter, opts ports.FindOptions, collection string) ([]T, error) {
+	coll := r.collection(collection)
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	findOptions := options.Find().SetSkip(opts.Skip).SetLimit(opts.Limit)
+	if len(opts.Sort) > 0 {
+		findOptions.SetSort(sortToBSON(opts.Sort))
+	}
+	cursor, err := coll.Find(ctx, filterToBSON(filter), findOptions)
 	if err != nil {
 		return nil, err
 	}
-	return result, nil
+	results := []T{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *repository[T]) Count(ctx context.Context, filter ports.Filter, collection string) (int64, error) {
+	coll := r.collection(collection)
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return coll.CountDocuments(ctx, filterToBSON(filter))
+}
+
+func (r *repository[T]) GetAll(ctx context.Context, collection string) ([]T, error) {
+	return r.Find(ctx, ports.Filter{}, ports.FindOptions{}, collection)
 }
 
-func (r *repository[T]) InsertData(workinforamtion interface{}, collection string) (bool, error) {
-	coll := r.db.Database("bhagabad_gita").Collection(collection)
-	_, err := coll.InsertOne(context.TODO(), workinforamtion)
+func (r *repository[T]) GetByField(ctx context.Context, field string, field_value string, collection string) (T, error) {
+	return r.FindOne(ctx, ports.Where(field, field_value), collection)
+}
+
+func (r *repository[T]) GetAllByField(ctx context.Context, field string, field_value string, collection string) ([]T, error) {
+	return r.Find(ctx, ports.Where(field, field_value), ports.FindOptions{}, collection)
+}
+
+func (r *repository[T]) Update(ctx context.Context, filter ports.Filter, model T, collection string) (bool, error) {
+	coll := r.collection(collection)
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	result, err := coll.ReplaceOne(ctx, filterToBSON(filter), model)
 	if err != nil {
-		return false, err
+		return false, duplicate(err)
 	}
-	return true, nil
+	return result.MatchedCount > 0, nil
 }
 
-func (r *repository[T]) GetData(username string, collection string) (interface{}, error) {
-	coll := r.db.Database("bhagabad_gita").Collection(collection)
-	filter := bson.D{{Key: "username", Value: username}}
-	var result interface{}
-	err := coll.FindOne(context.TODO(), filter).Decode(&result)
+func (r *repository[T]) Upsert(ctx context.Context, filter ports.Filter, model T, collection string) (bool, error) {
+	coll := r.collection(collection)
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	result, err := coll.ReplaceOne(ctx, filterToBSON(filter), model, options.Replace().SetUpsert(true))
 	if err != nil {
-		return nil, err
+		return false, duplicate(err)
 	}
-	return result, nil
+	return result.UpsertedCount > 0, nil
 }
 
-func (r *repository[T]) GetAll(collection string) ([]map[string]interface{}, error) {
-	coll := r.db.Database("bhagabad_gita").Collection(collection)
-	cursor, err := coll.Find(context.TODO(), bson.D{})
-	if err != nil {
-		return nil, err
+func (r *repository[T]) SetFields(ctx context.Context, filter ports.Filter, fields map[string]interface{}, collection string) (bool, error) {
+	coll := r.collection(collection)
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	set := bson.D{}
+	for field, value := range fields {
+		set = append(set, bson.E{Key: field, Value: value})
 	}
-	defer cursor.Close(context.TODO())
-	var results []map[string]interface{}
-	for cursor.Next(context.TODO()) {
-		var result map[string]interface{}
-		err := cursor.Decode(&result)
-		if err != nil {
-			return nil, err
-		}
-		results = append(results, result)
+	result, err := coll.UpdateOne(ctx, filterToBSON(filter), bson.D{{Key: "$set", Value: set}})
+	if err != nil {
+		return false, duplicate(err)
 	}
-	if err := cursor.Err(); err != nil {
-		return nil, err
+	return result.MatchedCount > 0, nil
+}
+
+func (r *repository[T]) Delete(ctx context.Context, filter ports.Filter, collection string) (bool, error) {
+	coll := r.collection(collection)
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	result, err := coll.DeleteOne(ctx, filterToBSON(filter))
+	if err != nil {
+		return false, err
 	}
-	fmt.Println("The result is here", results)
-	return results, nil
+	return result.DeletedCount > 0, nil
 }
 
-func (r *repository[T]) GetAllByField(field string, field_value string, collection string) ([]map[string]interface{}, error) {
-	coll := r.db.Database("bhagabad_gita").Collection(collection)
-	filter := bson.D{{Key: field, Value: field_value}}
-	cursor, err := coll.Find(context.TODO(), filter)
+func (r *repository[T]) UpdateByField(ctx context.Context, field string, field_value string, model T, collection string) (bool, error) {
+	return r.Update(ctx, ports.Where(field, field_value), model, collection)
+}
+
+func (r *repository[T]) DeleteByField(ctx context.Context, field string, field_value string, collection string) (bool, error) {
+	return r.Delete(ctx, ports.Where(field, field_value), collection)
+}
+
+func (r *repository[T]) FindRaw(ctx context.Context, filter interface{}, results interface{}, collection string) error {
+	coll := r.collection(collection)
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	cursor, err := coll.Find(ctx, filter)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer cursor.Close(context.TODO())
-	var results []map[string]interface{}
-	for cursor.Next(context.TODO()) {
-		var result map[string]interface{}
-		err := cursor.Decode(&result)
-		if err != nil {
-			return nil, err
-		}
-		results = append(results, result)
+	return cursor.All(ctx, results)
+}
+
+func (r *repository[T]) AggregateRaw(ctx context.Context, pipeline interface{}, results interface{}, collection string) error {
+	coll := r.collection(collection)
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
 	}
-	if err := cursor.Err(); err != nil {
-		return nil, err
+	return cursor.All(ctx, results)
+}
+
+// duplicate maps unique index violations to domain.ErrDuplicate.
+func duplicate(err error) error {
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("%w: %v", domain.ErrDuplicate, err)
 	}
-	return results, nil
+	return err
 }
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+)
+
+// Store is the storage backend repositories are initialized with: MongoDB,
+// or process memory for tests and local development.
+type Store struct {
+	conn   *Connection
+	memory *memoryStore
+}
+
+func NewMongoStore(conn *Connection) *Store {
+	return &Store{conn: conn}
+}
+
+// NewMemoryStore keeps every collection in memory. Its contents are lost
+// when the process exits.
+func NewMemoryStore() *Store {
+	return &Store{memory: newMemoryStore()}
+}
+
+func newBaseRepository[T any](store *Store) ports.BaseRepository[T] {
+	if store.memory != nil {
+		return &memoryRepository[T]{store: store.memory}
+	}
+	return newRepository[T](store.conn.client, store.conn.conf)
+}
+
+// Name and Check make the store a ports.HealthCheck.
+func (s *Store) Name() string {
+	return "mongodb"
+}
+
+func (s *Store) Check(ctx context.Context) (map[string]interface{}, error) {
+	if s.conn == nil {
+		return map[string]interface{}{"backend": "memory"}, nil
+	}
+	details := map[string]interface{}{"backend": "mongo", "database": s.conn.conf.Database}
+	if err := s.conn.Ping(ctx); err != nil {
+		return details, err
+	}
+	if !s.conn.Ready() {
+		return details, errors.New("shutting down")
+	}
+	return details, nil
+}
+
+// Drain makes the store report not ready from the moment shutdown begins.
+func (s *Store) Drain() {
+	if s.conn != nil {
+		s.conn.Drain()
+	}
+}
+
+// Close disconnects from MongoDB. It is a no-op for the memory store.
+func (s *Store) Close(ctx context.Context) error {
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Disconnect(ctx)
+}
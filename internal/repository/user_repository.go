@@ -2,18 +2,18 @@ package repository
 
 import (
 	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
-	"go.mongodb.org/mongo-driver/mongo"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
 )
 
 var UserRepo *UserRepository
 
 type UserRepository struct {
-	*repository[domain.User]
+	ports.BaseRepository[domain.User]
 }
 
-func (r *UserRepository) Initialize(db *mongo.Client) *UserRepository {
+func (r *UserRepository) Initialize(store *Store) *UserRepository {
 	UserRepo = &UserRepository{
-		repository: &repository[domain.User]{db: db},
+		BaseRepository: newBaseRepository[domain.User](store),
 	}
 	return UserRepo
 }
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+)
+
+const (
+	VERSES_COLLECTION           = "verses"
+	CHAPTERS_COLLECTION         = "chapters"
+	VERSE_OF_THE_DAY_COLLECTION = "verse_of_the_day"
+)
+
+var VerseRepo *VerseRepository
+
+type VerseRepository struct {
+	ports.BaseRepository[domain.Verse]
+	chapters      ports.BaseRepository[domain.Chapter]
+	verseOfTheDay ports.BaseRepository[domain.VerseOfTheDay]
+}
+
+func (r *VerseRepository) Initialize(store *Store) *VerseRepository {
+	VerseRepo = &VerseRepository{
+		BaseRepository: newBaseRepository[domain.Verse](store),
+		chapters:       newBaseRepository[domain.Chapter](store),
+		verseOfTheDay:  newBaseRepository[domain.VerseOfTheDay](store),
+	}
+	return VerseRepo
+}
+
+var verseOrder = []ports.SortField{ports.Asc("chapter"), ports.Asc("verse")}
+
+func (r *VerseRepository) GetChapters(ctx context.Context) ([]domain.Chapter, error) {
+	return r.chapters.Find(ctx, ports.Filter{}, ports.FindOptions{Sort: []ports.SortField{ports.Asc("number")}}, CHAPTERS_COLLECTION)
+}
+
+func (r *VerseRepository) GetChapter(ctx context.Context, number int) (domain.Chapter, error) {
+	return r.chapters.FindOne(ctx, ports.Where("number", number), CHAPTERS_COLLECTION)
+}
+
+func (r *VerseRepository) GetVerse(ctx context.Context, ref domain.VerseRef) (domain.Verse, error) {
+	return r.FindOne(ctx, ports.Where("chapter", ref.Chapter).And("verse", ref.Verse), VERSES_COLLECTION)
+}
+
+// GetVerseRange returns up to limit verses in reading order. A range spanning
+// chapters loads the chapters it touches and trims the partial ones.
+func (r *VerseRepository) GetVerseRange(ctx context.Context, verseRange domain.VerseRange, limit int64) ([]domain.Verse, error) {
+	from, to := verseRange.From, verseRange.To
+	if from.Chapter == to.Chapter {
+		filter := ports.Where("chapter", from.Chapter).AndOp("verse", ports.OpGte, from.Verse).AndOp("verse", ports.OpLte, to.Verse)
+		return r.Find(ctx, filter, ports.FindOptions{Sort: verseOrder, Limit: limit}, VERSES_COLLECTION)
+	}
+	filter := ports.Filter{}.AndOp("chapter", ports.OpGte, from.Chapter).AndOp("chapter", ports.OpLte, to.Chapter)
+	verses, err := r.Find(ctx, filter, ports.FindOptions{Sort: verseOrder}, VERSES_COLLECTION)
+	if err != nil {
+		return nil, err
+	}
+	inRange := []domain.Verse{}
+	for _, verse := range verses {
+		ref := domain.VerseRef{Chapter: verse.Chapter, Verse: verse.Verse}
+		if ref.Before(from) || to.Before(ref) {
+			continue
+		}
+		if int64(len(inRange)) == limit {
+			break
+		}
+		inRange = append(inRange, verse)
+	}
+	return inRange, nil
+}
+
+func (r *VerseRepository) UpsertChapter(ctx context.Context, chapter domain.Chapter) error {
+	_, err := r.chapters.Upsert(ctx, ports.Where("number", chapter.Number), chapter, CHAPTERS_COLLECTION)
+	return err
+}
+
+func (r *VerseRepository) UpsertVerse(ctx context.Context, verse domain.Verse) error {
+	_, err := r.Upsert(ctx, ports.Where("chapter", verse.Chapter).And("verse", verse.Verse), verse, VERSES_COLLECTION)
+	return err
+}
+
+func (r *VerseRepository) GetVerseRefs(ctx context.Context) ([]domain.VerseRef, error) {
+	verses, err := r.Find(ctx, ports.Filter{}, ports.FindOptions{Sort: verseOrder}, VERSES_COLLECTION)
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]domain.VerseRef, 0, len(verses))
+	for _, verse := range verses {
+		refs = append(refs, domain.VerseRef{Chapter: verse.Chapter, Verse: verse.Verse})
+	}
+	return refs, nil
+}
+
+func (r *VerseRepository) GetVerseOfTheDay(ctx context.Context, date string) (domain.VerseOfTheDay, error) {
+	return r.verseOfTheDay.FindOne(ctx, ports.Where("date", date), VERSE_OF_THE_DAY_COLLECTION)
+}
+
+func (r *VerseRepository) SaveVerseOfTheDay(ctx context.Context, entry domain.VerseOfTheDay) error {
+	_, err := r.verseOfTheDay.Upsert(ctx, ports.Where("date", entry.Date), entry, VERSE_OF_THE_DAY_COLLECTION)
+	return err
+}
@@ -2,6 +2,8 @@ package routes
 
 import (
 	"github.com/asifrahaman13/bhagabad_gita/internal/handlers"
+	"github.com/asifrahaman13/bhagabad_gita/internal/metrics"
+	"github.com/asifrahaman13/bhagabad_gita/internal/middleware"
 	"github.com/gin-gonic/gin"
 )
 
@@ -18,10 +20,57 @@ func SetupPublicRoutes(router *gin.Engine) {
 	public := router.Group("/v1")
 	{
 		public.GET("/public", handlers.UserHandler.PublicApi)
+		public.POST("/chat/stream", middleware.OptionalAuthMiddleware(), handlers.ChatHandler.Stream)
+		public.GET("/ask", middleware.OptionalAuthMiddleware(), handlers.ChatHandler.Ask)
+		public.POST("/ask", middleware.OptionalAuthMiddleware(), handlers.ChatHandler.Ask)
+		public.GET("/search", handlers.SearchHandler.Search)
+		public.GET("/chapters", handlers.VerseHandler.GetChapters)
+		public.GET("/chapters/:n", handlers.VerseHandler.GetChapter)
+		public.GET("/verses/:chapter", handlers.VerseHandler.GetVerses)
+		public.GET("/verses/:chapter/:verse", handlers.VerseHandler.GetVerses)
+		public.GET("/verse-of-the-day", handlers.VerseHandler.GetVerseOfTheDay)
+		public.POST("/feedback", middleware.OptionalAuthMiddleware(), handlers.FeedbackHandler.Submit)
 	}
 }
 
+func SetupPrivateRoutes(router *gin.Engine) {
+	private := router.Group("/v1")
+	private.Use(middleware.AuthMiddleware())
+	{
+		private.GET("/me", handlers.UserHandler.GetMe)
+		private.PATCH("/me", handlers.UserHandler.UpdateMe)
+		private.GET("/history", handlers.ChatHandler.History)
+		private.GET("/bookmarks", handlers.BookmarkHandler.List)
+		private.POST("/bookmarks", handlers.BookmarkHandler.Create)
+		private.GET("/bookmarks/export", handlers.BookmarkHandler.Export)
+		private.GET("/bookmarks/:id", handlers.BookmarkHandler.Get)
+		private.PATCH("/bookmarks/:id", handlers.BookmarkHandler.Update)
+		private.DELETE("/bookmarks/:id", handlers.BookmarkHandler.Delete)
+	}
+	admin := router.Group("/v1/admin")
+	admin.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+	{
+		admin.GET("/feedback/report", handlers.FeedbackHandler.Report)
+	}
+}
+
+// SetupHealthRoutes serves the probes at the root, outside /v1, where
+// orchestrators expect them.
+func SetupHealthRoutes(router *gin.Engine) {
+	router.GET("/healthz", handlers.HealthHandler.Live)
+	router.GET("/readyz", handlers.HealthHandler.Ready)
+}
+
+// SetupMetricsRoutes exposes the Prometheus metrics at the root, next to the
+// probes.
+func SetupMetricsRoutes(router *gin.Engine) {
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+}
+
 func InitializeRoutes(router *gin.Engine) {
+	SetupHealthRoutes(router)
+	SetupMetricsRoutes(router)
 	SetupV1Routes(router)
 	SetupPublicRoutes(router)
+	SetupPrivateRoutes(router)
 }
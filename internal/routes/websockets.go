@@ -1,217 +1,150 @@
 package routes
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/asifrahaman13/bhagabad_gita/internal/config"
 	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
 	"github.com/asifrahaman13/bhagabad_gita/internal/helper"
+	"github.com/asifrahaman13/bhagabad_gita/internal/metrics"
 	"github.com/gorilla/websocket"
-	"github.com/qdrant/go-client/qdrant"
-	"io"
 	"net/http"
-	"os"
 	"strings"
 )
 
-const (
-	OUTPUT_PATH          = "static/output.json"
-	COLLECTION_NAME      = "test_collection"
-	EMBEDDING_MODEL_NAME = "mxbai-embed-large"
-	EMBEDDING_URL        = "http://localhost:11434/api/embeddings"
-)
+var Websocket *websocketHandler
 
-type EmbeddingService struct {
-	url string
+type websocketHandler struct {
+	chatService     ports.ChatService
+	feedbackService ports.FeedbackService
+	userService     ports.UserService
+	conf            config.WebsocketConfig
 }
 
-type QdrantService struct {
-	client *qdrant.Client
+func (w *websocketHandler) Initialize(chatService ports.ChatService, feedbackService ports.FeedbackService, userService ports.UserService, conf config.WebsocketConfig) {
+	Websocket = &websocketHandler{
+		chatService:     chatService,
+		feedbackService: feedbackService,
+		userService:     userService,
+		conf:            conf,
+	}
 }
 
-func (e *EmbeddingService) GetEmbedding(content string) ([]float32, error) {
-	payload := map[string]string{
-		"model":  EMBEDDING_MODEL_NAME,
-		"prompt": fmt.Sprintf("Represent this sentence for searching relevant passages: %s", content),
-	}
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		return nil, fmt.Errorf("error marshalling payload: %v", err)
-	}
-	resp, err := http.Post(e.url, "application/json", bytes.NewBuffer(payloadBytes))
-	if err != nil {
-		return nil, fmt.Errorf("error making request to embedding API: %v", err)
+// WebSocketUsername authenticates the upgrade request from a bearer token in
+// the Authorization header or, for browsers, the token query parameter.
+// Anonymous connections return an empty username; an invalid token is an
+// error.
+func WebSocketUsername(r *http.Request) (string, error) {
+	token := r.URL.Query().Get("token")
+	if parts := strings.Split(r.Header.Get("Authorization"), " "); len(parts) == 2 && parts[0] == "Bearer" {
+		token = parts[1]
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("error response from embedding API: %s", string(body))
+	if token == "" {
+		return "", nil
 	}
-	var result struct {
-		Embedding []float32 `json:"embedding"`
-	}
-	err = json.NewDecoder(resp.Body).Decode(&result)
+	claims, err := helper.VerifyToken(token)
 	if err != nil {
-		return nil, fmt.Errorf("error decoding embedding API response: %v", err)
+		return "", err
 	}
-	return result.Embedding, nil
+	username, _ := claims["username"].(string)
+	return username, nil
 }
 
-func (q *QdrantService) VectorSearch(query string, embeddingService *EmbeddingService) ([]map[string]interface{}, error) {
-	embedding, err := embeddingService.GetEmbedding(query)
-	if err != nil {
-		return nil, err
+func answerQuestion(ctx context.Context, session *wsSession, askId string, ask domain.AskData) {
+	defer session.finish(askId)
+	if session.username != "" {
+		ask = Websocket.userService.ApplyPreferences(ctx, session.username, ask)
+		ask.Username = session.username
 	}
-	limit := uint64(3)
-	searchResult, err := q.client.Query(context.Background(), &qdrant.QueryPoints{
-		CollectionName: COLLECTION_NAME,
-		Query:          qdrant.NewQuery(embedding...),
-		Limit:          &limit,
-		WithPayload:    qdrant.NewWithPayload(true),
+	err := Websocket.chatService.Ask(ctx, ask, func(messageType string, data interface{}) bool {
+		return session.send(messageType, askId, data)
 	})
 	if err != nil {
-		return nil, err
-	}
-	var results []map[string]interface{}
-	for _, res := range searchResult {
-		fmt.Println(res.Payload["pageNum"].GetDoubleValue())
-		results = append(results, map[string]interface{}{
-			"pageNum": res.Payload["pageNum"].GetDoubleValue(),
-			"content": res.Payload["pageContent"].GetStringValue(),
-		})
+		session.sendProtocolError(askId, err)
 	}
-	return results, nil
 }
 
-func chatBotResponse(prompt domain.WebsocketMessage, conn *websocket.Conn) {
-	config, err := config.NewConfig()
-	if err != nil {
-		fmt.Println("Error getting config:", err)
-		conn.WriteMessage(websocket.TextMessage, []byte("Error getting config"))
+func submitFeedback(session *wsSession, feedbackId string, input domain.FeedbackInput) {
+	if _, err := Websocket.feedbackService.Submit(context.Background(), session.username, input); err != nil {
+		fmt.Println("Error storing feedback:", err)
+		session.sendProtocolError(feedbackId, err)
 		return
 	}
-	postUrl := config.LLamaUrl
-	fmt.Printf("The message is from the client: %s and the client is: %s, message id is: %d, message type is: %s", prompt.Payload, prompt.ClientId, prompt.MessageId, prompt.MsgType)
-	body := []byte(fmt.Sprintf(`{
-		"model": "llama3.1",
-		"stream": true,
-		"prompt": "%s"
-	}`, prompt.Payload))
-
-	req, err := http.NewRequest("POST", postUrl, bytes.NewBuffer(body))
-	if err != nil {
-		fmt.Println("Error creating request:", err)
-		conn.WriteMessage(websocket.TextMessage, []byte("Error creating request"))
-		return
-	}
-	req.Header.Add("Content-Type", "application/json")
-	client := &http.Client{}
-	res, err := client.Do(req)
-	if err != nil {
-		fmt.Println("Error making request:", err)
-		conn.WriteMessage(websocket.TextMessage, []byte("Error making request"))
-		return
-	}
-	defer res.Body.Close()
-
-	var buffer strings.Builder
-	for {
-		var chatResponse domain.ChatResponse
-		err = json.NewDecoder(res.Body).Decode(&chatResponse)
-		if err != nil {
-			fmt.Println("Error decoding response:", err)
-			conn.WriteMessage(websocket.TextMessage, []byte(""))
-			return
-		}
-		buffer.WriteString(chatResponse.Response)
-		if helper.IsSentenceEnd(*bytes.NewBufferString(buffer.String())) {
-			textMessages := domain.WebsocketMessage{
-				ClientId:  prompt.ClientId,
-				MessageId: prompt.MessageId,
-				Payload:   buffer.String(),
-				MsgType:   "server",
-			}
-
-			jsonStringMessage, err := json.Marshal(textMessages)
-			if err != nil {
-				fmt.Println("Error marshaling message:", err)
-				conn.WriteMessage(websocket.TextMessage, []byte("Error marshaling message"))
-				return
-			}
-
-			err = conn.WriteMessage(websocket.TextMessage, jsonStringMessage)
-			if err != nil {
-				fmt.Println("Error sending message:", err)
-				conn.Close()
-				return
-			}
-			buffer.Reset()
-		}
-	}
+	session.send(domain.MessageTypeAck, feedbackId, nil)
 }
 
-func NewEmbeddingService(url string) *EmbeddingService {
-	return &EmbeddingService{url: url}
-}
-func ErrorHandler(err error) {
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+func HandleWebSocketConnection(conn *websocket.Conn, username string) {
+	client := newWSClient(conn, Websocket.conf)
+	go client.writePump()
+	session := newWSSession(client, username)
+	if !Hub.register(session) {
+		client.CloseWith(websocket.CloseGoingAway, "server shutting down")
+		return
 	}
-}
-
-func NewQdrantService(host string, port int) *QdrantService {
-	client, err := qdrant.NewClient(&qdrant.Config{
-		Host: host,
-		Port: port,
-	})
-	ErrorHandler(err)
-	return &QdrantService{client: client}
-}
-
-func HandleWebSocketConnection(conn *websocket.Conn) {
+	metrics.WebsocketConnections.Inc()
+	defer func() {
+		metrics.WebsocketConnections.Dec()
+		session.cancelAll()
+		client.Close()
+		// The session only counts as gone once its last frame is written.
+		<-client.Stopped()
+		Hub.remove(session)
+	}()
 	for {
 		_, message, err := conn.ReadMessage()
 		if err != nil {
 			fmt.Println("Error reading message:", err)
-			conn.Close()
+			if code, reason := closeCodeForReadError(err); code != -1 {
+				client.CloseWith(code, reason)
+			}
 			break
 		}
-		var messageStruct domain.WebsocketMessage
-		err = json.Unmarshal(message, &messageStruct)
+		client.Touch()
+		envelope, err := domain.ParseClientEnvelope(message)
 		if err != nil {
 			fmt.Println("Error decoding message:", err)
+			metrics.WebsocketMessagesTotal.WithLabelValues(metrics.DIRECTION_IN, "invalid").Inc()
+			session.sendProtocolError(envelope.Id, err)
+			continue
 		}
-		embeddingService := NewEmbeddingService(EMBEDDING_URL)
-		qdrantService := NewQdrantService("localhost", 6334)
-		result, err := qdrantService.VectorSearch(messageStruct.Payload, embeddingService)
-		if err != nil {
-			fmt.Println("Error searching vectors:", err)
-			return
-		}
-		allContext := ""
-		for _, res := range result {
-			trimmedContent := strings.TrimSpace(res["content"].(string))
-			allContext += trimmedContent + "\n"
-		}
-		fmt.Println(allContext)
-		allContext = strings.ReplaceAll(allContext, "\n", " ")
-		messageStruct.Payload = fmt.Sprintf("You are an expert in spiritaul answers. User has the following query. Answer the query: %s . Also you have some additional context to give better ansser: %s", messageStruct.Payload, allContext)
-		go chatBotResponse(messageStruct, conn)
-		output:=map[string]interface{}{
-			"clientId":  messageStruct.ClientId,
-			"messageId": messageStruct.MessageId,
-			"msgType":   "status",
-			"payload":   result,
-		}
-		jsonResult, err := json.Marshal(output)
-		if err != nil {
-			fmt.Println("Error marshaling result:", err)
-			conn.WriteMessage(websocket.TextMessage, []byte("Error marshaling result"))
-			return
+		metrics.WebsocketMessagesTotal.WithLabelValues(metrics.DIRECTION_IN, envelope.Type).Inc()
+		switch envelope.Type {
+		case domain.MessageTypeAsk:
+			var ask domain.AskData
+			json.Unmarshal(envelope.Data, &ask)
+			done, ok := Hub.startGeneration()
+			if !ok {
+				session.sendError(envelope.Id, domain.ErrorCodeUnavailable, "the server is shutting down")
+				continue
+			}
+			ctx, ok := session.start(envelope.Id)
+			if !ok {
+				done()
+				session.sendError(envelope.Id, domain.ErrorCodeInvalidMessage, "an ask with this id is already in progress")
+				continue
+			}
+			go func() {
+				defer done()
+				answerQuestion(ctx, session, envelope.Id, ask)
+			}()
+		case domain.MessageTypeSubscribe, domain.MessageTypeUnsubscribe:
+			var subscription domain.SubscriptionData
+			json.Unmarshal(envelope.Data, &subscription)
+			if envelope.Type == domain.MessageTypeSubscribe {
+				Hub.subscribe(subscription.Topic, session)
+			} else {
+				Hub.unsubscribe(subscription.Topic, session)
+			}
+		case domain.MessageTypeFeedback:
+			var feedback domain.FeedbackInput
+			json.Unmarshal(envelope.Data, &feedback)
+			go submitFeedback(session, envelope.Id, feedback)
+		case domain.MessageTypeCancel:
+			if !session.cancel(envelope.ReplyTo) {
+				session.sendError(envelope.Id, domain.ErrorCodeNotFound, "no ask in progress with this id")
+			}
 		}
-		conn.WriteMessage(websocket.TextMessage, jsonResult)
 	}
 }
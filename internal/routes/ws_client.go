@@ -0,0 +1,198 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/config"
+	"github.com/gorilla/websocket"
+	"sync"
+	"time"
+)
+
+// SEND_BUFFER_SIZE is the number of outbound messages queued per connection
+// before the client is considered too slow and gets disconnected.
+const SEND_BUFFER_SIZE = 256
+
+// wsClient owns a websocket connection and serialises every write through a
+// single writer goroutine, since gorilla/websocket allows only one concurrent writer.
+type wsClient struct {
+	conn      *websocket.Conn
+	conf      config.WebsocketConfig
+	send      chan []byte
+	closing   chan []byte
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+	idleTimer *time.Timer
+}
+
+func newWSClient(conn *websocket.Conn, conf config.WebsocketConfig) *wsClient {
+	client := &wsClient{
+		conn:    conn,
+		conf:    conf,
+		send:    make(chan []byte, SEND_BUFFER_SIZE),
+		closing: make(chan []byte, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	conn.SetReadLimit(conf.MaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(conf.PongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(conf.PongWait))
+	})
+	client.idleTimer = time.AfterFunc(conf.IdleTimeout, func() {
+		fmt.Println("Closing idle websocket connection")
+		client.CloseWith(websocket.CloseNormalClosure, "idle timeout")
+	})
+	return client
+}
+
+// writePump is the connection's only writer. It closes stopped once it has
+// written its last frame and closed the connection.
+func (c *wsClient) writePump() {
+	ticker := time.NewTicker(c.conf.PingInterval)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+		close(c.stopped)
+	}()
+	for {
+		select {
+		case message := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(c.conf.WriteWait))
+			if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				fmt.Println("Error sending message:", err)
+				c.Close()
+				return
+			}
+		case message := <-c.closing:
+			c.flush()
+			c.conn.SetWriteDeadline(time.Now().Add(c.conf.WriteWait))
+			if err := c.conn.WriteMessage(websocket.CloseMessage, message); err != nil {
+				fmt.Println("Error sending close frame:", err)
+			}
+			c.Close()
+			return
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(c.conf.WriteWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				fmt.Println("Error sending ping:", err)
+				c.Close()
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// flush writes the messages still queued, for a close that must not
+// overtake them.
+func (c *wsClient) flush() {
+	for {
+		select {
+		case message := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(c.conf.WriteWait))
+			if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				fmt.Println("Error sending message:", err)
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+// Touch records client activity and postpones the idle timeout.
+func (c *wsClient) Touch() {
+	c.idleTimer.Reset(c.conf.IdleTimeout)
+}
+
+// Send queues a message for the writer goroutine. A client whose buffer is
+// full is disconnected rather than allowed to block the producers.
+func (c *wsClient) Send(message []byte) bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+	select {
+	case c.send <- message:
+		return true
+	case <-c.done:
+		return false
+	default:
+		fmt.Println("Client is too slow, closing the connection")
+		c.CloseWith(websocket.CloseTryAgainLater, "client too slow")
+		return false
+	}
+}
+
+func (c *wsClient) SendJSON(v interface{}) error {
+	message, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if !c.Send(message) {
+		return fmt.Errorf("connection closed")
+	}
+	return nil
+}
+
+// CloseWith sends a close frame carrying the given close code before
+// shutting the connection down. WriteControl is safe to call concurrently
+// with the writer goroutine.
+func (c *wsClient) CloseWith(code int, reason string) {
+	select {
+	case <-c.done:
+		return
+	default:
+	}
+	message := websocket.FormatCloseMessage(code, reason)
+	if err := c.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(c.conf.WriteWait)); err != nil {
+		fmt.Println("Error sending close frame:", err)
+	}
+	c.Close()
+}
+
+// CloseAfterSend is CloseWith for a connection that is still being written
+// to: the close frame is sent by the writer goroutine after every message
+// already queued.
+func (c *wsClient) CloseAfterSend(code int, reason string) {
+	select {
+	case c.closing <- websocket.FormatCloseMessage(code, reason):
+	default:
+	}
+}
+
+func (c *wsClient) Close() {
+	c.closeOnce.Do(func() {
+		c.idleTimer.Stop()
+		close(c.done)
+	})
+}
+
+func (c *wsClient) Done() <-chan struct{} {
+	return c.done
+}
+
+// Stopped is closed once the writer goroutine has exited.
+func (c *wsClient) Stopped() <-chan struct{} {
+	return c.stopped
+}
+
+// closeCodeForReadError maps a read failure to the close code the server
+// should answer with, or -1 when no close frame should be sent.
+func closeCodeForReadError(err error) (int, string) {
+	if _, ok := err.(*websocket.CloseError); ok {
+		return -1, ""
+	}
+	if err == websocket.ErrReadLimit {
+		// gorilla/websocket has already replied with CloseMessageTooBig.
+		return -1, ""
+	}
+	if netErr, ok := err.(interface{ Timeout() bool }); ok && netErr.Timeout() {
+		return websocket.CloseGoingAway, "pong timeout"
+	}
+	return websocket.CloseInternalServerErr, "read error"
+}
@@ -0,0 +1,204 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/config"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	TEST_ASKS_PER_CONNECTION = 8
+	TEST_TOKENS_PER_ASK      = 10
+	TEST_NOTIFICATIONS       = 10
+	TEST_CONNECTIONS         = 3
+)
+
+// stubChatService streams TEST_TOKENS_PER_ASK numbered tokens for every
+// question. The question "block" streams nothing until it is cancelled.
+type stubChatService struct {
+	blocked chan struct{}
+}
+
+func (s *stubChatService) Ask(ctx context.Context, ask domain.AskData, emit ports.ChatEmitter) error {
+	if ask.Question == "block" {
+		s.blocked <- struct{}{}
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	for i := 0; i < TEST_TOKENS_PER_ASK; i++ {
+		if !emit(domain.MessageTypeToken, domain.TokenData{Text: fmt.Sprint(i)}) {
+			return ctx.Err()
+		}
+	}
+	emit(domain.MessageTypeDone, domain.DoneData{MessageId: ask.Question})
+	return nil
+}
+
+func (s *stubChatService) Answer(ctx context.Context, ask domain.AskData) (domain.Answer, error) {
+	return domain.Answer{}, nil
+}
+
+func (s *stubChatService) History(ctx context.Context, username string, query domain.PageQuery) (domain.Page[domain.AnswerRecord], error) {
+	return domain.Page[domain.AnswerRecord]{}, nil
+}
+
+// newTestServer serves HandleWebSocketConnection with a short ping interval
+// so that pings are written while answers are streaming.
+func newTestServer(t *testing.T, chat *stubChatService) string {
+	t.Helper()
+	(&websocketHandler{}).Initialize(chat, nil, nil, config.WebsocketConfig{
+		PingInterval:   20 * time.Millisecond,
+		PongWait:       5 * time.Second,
+		WriteWait:      5 * time.Second,
+		IdleTimeout:    time.Minute,
+		MaxMessageSize: 4096,
+	})
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Upgrade: %v", err)
+			return
+		}
+		HandleWebSocketConnection(conn, "")
+	}))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func dial(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	return conn
+}
+
+func writeEnvelope(conn *websocket.Conn, messageType string, id string, replyTo string, data interface{}) error {
+	envelope, err := domain.NewEnvelope(messageType, id, replyTo, data)
+	if err != nil {
+		return err
+	}
+	return conn.WriteJSON(envelope)
+}
+
+// waitForConnections fails the test unless every handler has returned.
+func waitForConnections(t *testing.T) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if !wait(ctx, &Hub.connections) {
+		t.Fatal("websocket handlers did not return")
+	}
+}
+
+// exchange subscribes to a topic, sends every ask without waiting for the
+// answers and reads until each ask is done and every notification arrived.
+// started is called for every frame; the first one shows the subscription
+// is in place.
+func exchange(conn *websocket.Conn, started func()) error {
+	if err := writeEnvelope(conn, domain.MessageTypeSubscribe, "sub", "", domain.SubscriptionData{Topic: domain.TopicVerseOfTheDay}); err != nil {
+		return err
+	}
+	for i := 0; i < TEST_ASKS_PER_CONNECTION; i++ {
+		id := fmt.Sprintf("ask-%d", i)
+		if err := writeEnvelope(conn, domain.MessageTypeAsk, id, "", domain.AskData{Question: id}); err != nil {
+			return err
+		}
+	}
+	tokens := make(map[string]int)
+	done := make(map[string]bool)
+	notifications := 0
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	for len(done) < TEST_ASKS_PER_CONNECTION || notifications < TEST_NOTIFICATIONS {
+		var envelope domain.Envelope
+		if err := conn.ReadJSON(&envelope); err != nil {
+			return fmt.Errorf("after %d answers and %d notifications: %w", len(done), notifications, err)
+		}
+		started()
+		switch envelope.Type {
+		case domain.MessageTypeNotification:
+			notifications++
+		case domain.MessageTypeToken:
+			var token domain.TokenData
+			if err := json.Unmarshal(envelope.Data, &token); err != nil {
+				return err
+			}
+			if done[envelope.ReplyTo] || token.Text != fmt.Sprint(tokens[envelope.ReplyTo]) {
+				return fmt.Errorf("%s: token %q out of order after %d tokens", envelope.ReplyTo, token.Text, tokens[envelope.ReplyTo])
+			}
+			tokens[envelope.ReplyTo]++
+		case domain.MessageTypeDone:
+			if tokens[envelope.ReplyTo] != TEST_TOKENS_PER_ASK || done[envelope.ReplyTo] {
+				return fmt.Errorf("%s: done after %d tokens", envelope.ReplyTo, tokens[envelope.ReplyTo])
+			}
+			done[envelope.ReplyTo] = true
+		default:
+			return fmt.Errorf("unexpected %s frame: %s", envelope.Type, envelope.Data)
+		}
+	}
+	return nil
+}
+
+// TestConcurrentWrites streams answers to several asks on several
+// connections while notifications are published to them, which all write
+// to the same connections at once. Run it with -race.
+func TestConcurrentWrites(t *testing.T) {
+	url := newTestServer(t, &stubChatService{})
+	var started sync.WaitGroup
+	started.Add(TEST_CONNECTIONS)
+	var clients sync.WaitGroup
+	for i := 0; i < TEST_CONNECTIONS; i++ {
+		conn := dial(t, url)
+		clients.Add(1)
+		go func() {
+			defer clients.Done()
+			defer conn.Close()
+			var once sync.Once
+			if err := exchange(conn, func() { once.Do(started.Done) }); err != nil {
+				t.Error(err)
+				once.Do(started.Done)
+			}
+		}()
+	}
+	started.Wait()
+	for i := 0; i < TEST_NOTIFICATIONS; i++ {
+		Hub.Publish(domain.TopicVerseOfTheDay, i)
+	}
+	clients.Wait()
+	waitForConnections(t)
+}
+
+// TestDisconnectCancelsAsks checks that an answer still being generated is
+// cancelled when its client goes away, and that the handler then returns.
+func TestDisconnectCancelsAsks(t *testing.T) {
+	chat := &stubChatService{blocked: make(chan struct{})}
+	url := newTestServer(t, chat)
+	conn := dial(t, url)
+	if err := writeEnvelope(conn, domain.MessageTypeAsk, "ask", "", domain.AskData{Question: "block"}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-chat.blocked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("ask did not start")
+	}
+	conn.Close()
+	waitForConnections(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if !wait(ctx, &Hub.generations) {
+		t.Fatal("blocked ask was not cancelled")
+	}
+}
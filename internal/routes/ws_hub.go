@@ -0,0 +1,149 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/gorilla/websocket"
+	"sync"
+	"time"
+)
+
+// CLOSE_GRACE_PERIOD is how long sessions get to write their close frames
+// and cancelled answers to return once ctx has already expired.
+const CLOSE_GRACE_PERIOD = 5 * time.Second
+
+// Hub fans server-pushed notifications out to the websocket sessions
+// subscribed to each topic. It also tracks every open session and the
+// answers being generated on them so the server can shut down gracefully.
+var Hub = &wsHub{
+	subscribers: make(map[string]map[*wsSession]bool),
+	sessions:    make(map[*wsSession]bool),
+}
+
+type wsHub struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[*wsSession]bool
+	sessions    map[*wsSession]bool
+	closing     bool
+	generations sync.WaitGroup
+	connections sync.WaitGroup
+}
+
+// register adds a new session unless the hub is shutting down.
+func (h *wsHub) register(session *wsSession) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closing {
+		return false
+	}
+	h.sessions[session] = true
+	h.connections.Add(1)
+	return true
+}
+
+// startGeneration counts an answer in progress until the returned function
+// is called. It fails once shutdown has begun.
+func (h *wsHub) startGeneration() (func(), bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closing {
+		return nil, false
+	}
+	h.generations.Add(1)
+	return h.generations.Done, true
+}
+
+// Shutdown refuses new sessions and asks, waits until ctx is done for the
+// answers in progress, cancels any still running and then closes every
+// session with a "going away" close frame. It returns once the cancelled
+// answers have returned and every close frame is written, so that the
+// stores can be closed afterwards. If ctx has already expired by then, they
+// get CLOSE_GRACE_PERIOD more.
+func (h *wsHub) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.closing = true
+	h.mu.Unlock()
+	var err error
+	if !wait(ctx, &h.generations) {
+		err = fmt.Errorf("answers still in progress: %w", ctx.Err())
+	}
+	h.mu.RLock()
+	sessions := make([]*wsSession, 0, len(h.sessions))
+	for session := range h.sessions {
+		sessions = append(sessions, session)
+	}
+	h.mu.RUnlock()
+	for _, session := range sessions {
+		session.cancelAll()
+		session.client.CloseAfterSend(websocket.CloseGoingAway, "server shutting down")
+	}
+	graceCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), CLOSE_GRACE_PERIOD)
+	defer cancel()
+	if ctx.Err() == nil {
+		graceCtx = ctx
+	}
+	if !wait(graceCtx, &h.generations) {
+		return fmt.Errorf("cancelled answers still running: %w", graceCtx.Err())
+	}
+	if !wait(graceCtx, &h.connections) {
+		return fmt.Errorf("websocket connections still open: %w", graceCtx.Err())
+	}
+	return err
+}
+
+// wait reports whether group finished before ctx was done.
+func wait(ctx context.Context, group *sync.WaitGroup) bool {
+	finished := make(chan struct{})
+	go func() {
+		group.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (h *wsHub) subscribe(topic string, session *wsSession) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subscribers[topic] == nil {
+		h.subscribers[topic] = make(map[*wsSession]bool)
+	}
+	h.subscribers[topic][session] = true
+}
+
+func (h *wsHub) unsubscribe(topic string, session *wsSession) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subscribers[topic], session)
+}
+
+func (h *wsHub) remove(session *wsSession) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.sessions[session] {
+		h.connections.Done()
+	}
+	delete(h.sessions, session)
+	for _, sessions := range h.subscribers {
+		delete(sessions, session)
+	}
+}
+
+// Publish sends outside the lock: closing a slow client writes a close
+// frame, which must not hold up subscribe, unsubscribe or other publishers.
+func (h *wsHub) Publish(topic string, data interface{}) {
+	h.mu.RLock()
+	sessions := make([]*wsSession, 0, len(h.subscribers[topic]))
+	for session := range h.subscribers[topic] {
+		sessions = append(sessions, session)
+	}
+	h.mu.RUnlock()
+	for _, session := range sessions {
+		session.send(domain.MessageTypeNotification, "", domain.NotificationData{Topic: topic, Payload: data})
+	}
+}
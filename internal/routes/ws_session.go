@@ -0,0 +1,92 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	"github.com/asifrahaman13/bhagabad_gita/internal/metrics"
+	"github.com/google/uuid"
+	"sync"
+)
+
+// wsSession tracks the asks in flight on one connection so that they can be
+// cancelled individually or all at once when the connection goes away.
+// Username is empty for anonymous connections.
+type wsSession struct {
+	client   *wsClient
+	username string
+	mu       sync.Mutex
+	inFlight map[string]context.CancelFunc
+}
+
+func newWSSession(client *wsClient, username string) *wsSession {
+	return &wsSession{
+		client:   client,
+		username: username,
+		inFlight: make(map[string]context.CancelFunc),
+	}
+}
+
+func (s *wsSession) start(askId string) (context.Context, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.inFlight[askId]; exists {
+		return nil, false
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.inFlight[askId] = cancel
+	return ctx, true
+}
+
+func (s *wsSession) finish(askId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cancel, exists := s.inFlight[askId]; exists {
+		cancel()
+		delete(s.inFlight, askId)
+	}
+}
+
+func (s *wsSession) cancel(askId string) bool {
+	s.mu.Lock()
+	cancel, exists := s.inFlight[askId]
+	s.mu.Unlock()
+	if exists {
+		cancel()
+	}
+	return exists
+}
+
+func (s *wsSession) cancelAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, cancel := range s.inFlight {
+		cancel()
+	}
+}
+
+func (s *wsSession) send(messageType string, replyTo string, data interface{}) bool {
+	envelope, err := domain.NewEnvelope(messageType, uuid.New().String(), replyTo, data)
+	if err != nil {
+		fmt.Println("Error marshaling message:", err)
+		return false
+	}
+	if err := s.client.SendJSON(envelope); err != nil {
+		return false
+	}
+	metrics.WebsocketMessagesTotal.WithLabelValues(metrics.DIRECTION_OUT, messageType).Inc()
+	return true
+}
+
+func (s *wsSession) sendError(replyTo string, code string, message string) bool {
+	return s.send(domain.MessageTypeError, replyTo, domain.ErrorData{Code: code, Message: message})
+}
+
+func (s *wsSession) sendProtocolError(replyTo string, err error) bool {
+	var protocolErr *domain.ProtocolError
+	if errors.As(err, &protocolErr) {
+		return s.sendError(replyTo, protocolErr.Code, protocolErr.Message)
+	}
+	return s.sendError(replyTo, domain.ErrorCodeInternal, "internal server error")
+}
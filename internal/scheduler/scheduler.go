@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
+	"github.com/robfig/cron/v3"
+	"time"
+)
+
+// Scheduler runs registered jobs on cron schedules. Overlapping runs of the
+// same job are skipped and panics are recovered so one job can't stop the rest.
+type Scheduler struct {
+	cron *cron.Cron
+}
+
+func NewScheduler(location *time.Location) *Scheduler {
+	return &Scheduler{
+		cron: cron.New(
+			cron.WithLocation(location),
+			cron.WithChain(cron.Recover(cron.DefaultLogger), cron.SkipIfStillRunning(cron.DefaultLogger)),
+		),
+	}
+}
+
+// Register schedules job with a standard five-field cron spec or a
+// descriptor such as "@daily". An empty spec leaves the job disabled.
+func (s *Scheduler) Register(spec string, job ports.Job) error {
+	if spec == "" {
+		fmt.Printf("Job %s is disabled\n", job.Name())
+		return nil
+	}
+	_, err := s.cron.AddFunc(spec, func() {
+		start := time.Now()
+		if err := job.Run(); err != nil {
+			fmt.Printf("Job %s failed: %v\n", job.Name(), err)
+			return
+		}
+		fmt.Printf("Job %s finished in %s\n", job.Name(), time.Since(start))
+	})
+	if err != nil {
+		return fmt.Errorf("invalid schedule %q for job %s: %w", spec, job.Name(), err)
+	}
+	return nil
+}
+
+func (s *Scheduler) Start() {
+	s.cron.Start()
+}
+
+// Stop prevents new runs and returns a context that is done once running
+// jobs have finished.
+func (s *Scheduler) Stop() context.Context {
+	return s.cron.Stop()
+}
@@ -1,14 +1,26 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/config"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/ports"
 	service "github.com/asifrahaman13/bhagabad_gita/internal/core/services"
 	"github.com/asifrahaman13/bhagabad_gita/internal/handlers"
+	"github.com/asifrahaman13/bhagabad_gita/internal/helper"
+	"github.com/asifrahaman13/bhagabad_gita/internal/middleware"
+	"github.com/asifrahaman13/bhagabad_gita/internal/migrations"
 	"github.com/asifrahaman13/bhagabad_gita/internal/repository"
 	"github.com/asifrahaman13/bhagabad_gita/internal/routes"
+	"github.com/asifrahaman13/bhagabad_gita/internal/scheduler"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 	"github.com/gorilla/websocket"
 	"net/http"
@@ -21,7 +33,12 @@ var upgrader = websocket.Upgrader{
 }
 
 func main() {
-	if err := run(); err != nil {
+	conf, err := config.NewConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+	app, err := run(conf)
+	if err != nil {
 		log.Fatal(err)
 	}
 	parent_route := gin.Default()
@@ -33,30 +50,150 @@ func main() {
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
+	parent_route.Use(middleware.MetricsMiddleware())
 
 	routes.InitializeRoutes(parent_route)
 
 	parent_route.GET("/ws", func(c *gin.Context) {
-	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+		username, err := routes.WebSocketUsername(c.Request)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "invalid token",
+			})
+			return
+		}
+		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error": err.Error(),
 			})
 			return
 		}
-		go routes.HandleWebSocketConnection(conn)
+		go routes.HandleWebSocketConnection(conn, username)
 	})
-	log.Fatal(parent_route.Run())
+	server := &http.Server{
+		Addr:    ":" + conf.Server.Port,
+		Handler: parent_route,
+	}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	<-ctx.Done()
+	// A second signal kills the process without waiting.
+	stop()
+	fmt.Printf("Shutting down, waiting up to %s for requests and answers in progress\n", conf.Server.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), conf.Server.ShutdownTimeout)
+	defer cancel()
+	app.shutdown(shutdownCtx, server)
+}
+
+// app holds what has to be released when the server stops.
+type app struct {
+	store  *repository.Store
+	qdrant *service.QdrantService
+	jobs   *scheduler.Scheduler
 }
 
-func run() error {
-	db, err := repository.InitializeDB()
+// shutdown stops accepting HTTP and websocket connections, lets requests,
+// streamed answers and running jobs finish until ctx is done, and closes the
+// database clients only once the websocket answers it had to cancel have
+// returned and every close frame has been written.
+func (a *app) shutdown(ctx context.Context, server *http.Server) {
+	a.store.Drain()
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		if err := server.Shutdown(ctx); err != nil {
+			fmt.Println("Error shutting down HTTP server:", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		if err := routes.Hub.Shutdown(ctx); err != nil {
+			fmt.Println("Error shutting down websockets:", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		select {
+		case <-a.jobs.Stop().Done():
+		case <-ctx.Done():
+			fmt.Println("Error stopping scheduler: jobs still running")
+		}
+	}()
+	wg.Wait()
+	if err := a.qdrant.Close(); err != nil {
+		fmt.Println("Error closing Qdrant client:", err)
+	}
+	if err := a.store.Close(ctx); err != nil {
+		fmt.Println("Error disconnecting from MongoDB:", err)
+	}
+	fmt.Println("Server stopped")
+}
+
+func run(conf *config.Config) (*app, error) {
+	handlers.Base.Initialize(conf)
+	helper.InitializeTokens(conf.Auth.SecretKey, conf.Auth.TokenTTL)
+	store, err := openStore(conf)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	fmt.Println(db)
-	userRep := repository.UserRepo.Initialize(db)
-	users := service.InitializeUserService(userRep)
+	userRep := repository.UserRepo.Initialize(store)
+	llm := service.NewLLMService(conf.LLM)
+	users := service.InitializeUserService(userRep, llm, conf.AdminUsernames)
 	handlers.UserHandler.Initialize(users)
-	return nil
+	bookmarkRep := repository.BookmarkRepo.Initialize(store)
+	handlers.BookmarkHandler.Initialize(service.InitializeBookmarkService(bookmarkRep))
+	verseRep := repository.VerseRepo.Initialize(store)
+	verseOfTheDay := service.InitializeVerseOfTheDayService(verseRep, routes.Hub, conf.Scheduler.Location)
+	handlers.VerseHandler.Initialize(service.InitializeVerseService(verseRep), verseOfTheDay)
+	jobs := scheduler.NewScheduler(conf.Scheduler.Location)
+	if err := jobs.Register(conf.Scheduler.VerseOfTheDaySchedule, verseOfTheDay); err != nil {
+		return nil, err
+	}
+	jobs.Start()
+	qdrantService, err := service.NewQdrantService(conf.Qdrant)
+	if err != nil {
+		return nil, err
+	}
+	embeddingService := service.NewEmbeddingService(conf.Embedding)
+	answerRep := repository.AnswerRepo.Initialize(store)
+	chat := service.InitializeChatService(llm, embeddingService, qdrantService, answerRep)
+	feedback := service.InitializeFeedbackService(repository.FeedbackRepo.Initialize(store), answerRep)
+	handlers.ChatHandler.Initialize(chat, users)
+	handlers.FeedbackHandler.Initialize(feedback)
+	routes.Websocket.Initialize(chat, feedback, users, conf.Websocket)
+	handlers.SearchHandler.Initialize(service.InitializeSearchService(embeddingService, qdrantService))
+	checks := append([]ports.HealthCheck{store}, service.HealthChecks(llm, embeddingService, qdrantService)...)
+	handlers.HealthHandler.Initialize(service.InitializeHealthService(conf.Server.HealthCheckTimeout, checks...))
+	return &app{store: store, qdrant: qdrantService, jobs: jobs}, nil
+}
+
+// openStore connects the configured storage backend, applying pending
+// migrations first when it is MongoDB.
+func openStore(conf *config.Config) (*repository.Store, error) {
+	if conf.Storage == config.STORAGE_MEMORY {
+		fmt.Println("Using in-memory storage; data is lost on exit.")
+		return repository.NewMemoryStore(), nil
+	}
+	conn, err := repository.Connect(context.Background(), conf.Mongo)
+	if err != nil {
+		return nil, err
+	}
+	if conf.Mongo.MigrateOnStartup {
+		migrator := migrations.NewMigrator(conn.Database(), migrations.MIGRATIONS)
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			conn.Disconnect(context.Background())
+			return nil, err
+		}
+		for _, migration := range applied {
+			fmt.Printf("Applied migration %d: %s\n", migration.Version, migration.Description)
+		}
+	}
+	return repository.NewMongoStore(conn), nil
 }
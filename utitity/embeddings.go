@@ -9,17 +9,15 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"github.com/asifrahaman13/bhagabad_gita/internal/config"
 	"github.com/google/uuid"
 	"github.com/pdfcrowd/pdfcrowd-go"
 	"github.com/qdrant/go-client/qdrant"
 )
 
 const (
-	OutputPath         = "static/output.json"
-	FinalOutputPath    = "static/result.json"
-	CollectionName     = "test_collection"
-	EmbeddingModelName = "mxbai-embed-large"
-	EmbeddingURL       = "http://localhost:11434/api/embeddings"
+	OutputPath      = "static/output.json"
+	FinalOutputPath = "static/result.json"
 )
 
 // ErrorHandler handles errors
@@ -60,18 +58,20 @@ func (p *PDFProcessor) ConvertToJSON(inputPath string) {
 	fmt.Println("Data successfully written to output.json")
 }
 
-// EmbeddingService handles embedding requests
+// EmbeddingService handles embedding requests with the model the server
+// embeds queries with.
 type EmbeddingService struct {
-	url string
+	url   string
+	model string
 }
 
-func NewEmbeddingService(url string) *EmbeddingService {
-	return &EmbeddingService{url: url}
+func NewEmbeddingService(conf config.EmbeddingConfig) *EmbeddingService {
+	return &EmbeddingService{url: conf.URL, model: conf.Model}
 }
 
 func (e *EmbeddingService) GetEmbedding(content string) ([]float32, error) {
 	payload := map[string]string{
-		"model":  EmbeddingModelName,
+		"model":  e.model,
 		"prompt": fmt.Sprintf("Represent this sentence for searching relevant passages: %s", content),
 	}
 	payloadBytes, err := json.Marshal(payload)
@@ -97,28 +97,38 @@ func (e *EmbeddingService) GetEmbedding(content string) ([]float32, error) {
 	return result.Embedding, nil
 }
 
-// QdrantService handles interactions with Qdrant
+// QdrantService handles interactions with the collection the server
+// searches.
 type QdrantService struct {
-	client *qdrant.Client
+	client     *qdrant.Client
+	collection string
 }
 
-func NewQdrantService(host string, port int) *QdrantService {
+func NewQdrantService(conf config.QdrantConfig) *QdrantService {
 	client, err := qdrant.NewClient(&qdrant.Config{
-		Host: host,
-		Port: port,
+		Host: conf.Host,
+		Port: int(conf.Port),
 	})
 	ErrorHandler(err)
-	return &QdrantService{client: client}
+	return &QdrantService{client: client, collection: conf.Collection}
 }
 
-func (q *QdrantService) UpsertEmbeddings(pageData []map[string]interface{}, embeddingService *EmbeddingService) {
+// createCollection creates the collection sized for the embedding model's
+// vectors. It fails harmlessly when the collection already exists.
+func (q *QdrantService) createCollection(points []*qdrant.PointStruct) {
+	if len(points) == 0 {
+		return
+	}
 	q.client.CreateCollection(context.Background(), &qdrant.CreateCollection{
-		CollectionName: CollectionName,
+		CollectionName: q.collection,
 		VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
-			Size:     1024,
+			Size:     uint64(len(points[0].GetVectors().GetVector().GetData())),
 			Distance: qdrant.Distance_Cosine,
 		}),
 	})
+}
+
+func (q *QdrantService) UpsertEmbeddings(pageData []map[string]interface{}, embeddingService *EmbeddingService) {
 	var points []*qdrant.PointStruct
 	for _, page := range pageData {
 		pageContent := page["pageContent"].(string)
@@ -140,8 +150,9 @@ func (q *QdrantService) UpsertEmbeddings(pageData []map[string]interface{}, embe
 		}
 		points = append(points, point)
 	}
+	q.createCollection(points)
 	operationInfo, err := q.client.Upsert(context.Background(), &qdrant.UpsertPoints{
-		CollectionName: CollectionName,
+		CollectionName: q.collection,
 		Points:         points,
 	})
 	ErrorHandler(err)
@@ -155,7 +166,7 @@ func (q *QdrantService) VectorSearch(query string, embeddingService *EmbeddingSe
 	}
 	limit := uint64(3)
 	searchResult, err := q.client.Query(context.Background(), &qdrant.QueryPoints{
-		CollectionName: CollectionName,
+		CollectionName: q.collection,
 		Query:          qdrant.NewQuery(embedding...),
 		Limit:          &limit,
 		WithPayload:    qdrant.NewWithPayload(true),
@@ -253,22 +264,29 @@ func split() {
 func main() {
 	// split()
 	// pdfProcessor := NewPDFProcessor()
-	embeddingService := NewEmbeddingService(EmbeddingURL)
-	qdrantService := NewQdrantService("localhost", 6334)
+	conf, err := config.Load()
+	ErrorHandler(err)
+	embeddingService := NewEmbeddingService(conf.Embedding)
+	qdrantService := NewQdrantService(conf.Qdrant)
 
 	// // Step 1: Convert PDF to JSON
 	// pdfProcessor.ConvertToJSON("static/gita.pdf")
 	// // split()
 
 	// // Step 2: Load JSON and Upsert Embeddings
-	// jsonFile, err := os.ReadFile(FinalOutputPath)
+	// jsonFile, err = os.ReadFile(FinalOutputPath)
 	// ErrorHandler(err)
 	// var pageData []map[string]interface{}
 	// err = json.Unmarshal(jsonFile, &pageData)
 	// ErrorHandler(err)
 	// qdrantService.UpsertEmbeddings(pageData, embeddingService)
 
-	// Step 3: Perform Vector Search
+	// // Step 3: Load verses into MongoDB and embed them with chapter/verse payloads
+	// corpus := LoadVerseCorpus(VersesPath)
+	// IngestVerses(conf, corpus)
+	// qdrantService.UpsertVerseEmbeddings(corpus.Verses, embeddingService)
+
+	// Step 4: Perform Vector Search
 	query := "how a man should treat a wife"
 	results, err := qdrantService.VectorSearch(query, embeddingService)
 	ErrorHandler(err)
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/asifrahaman13/bhagabad_gita/internal/config"
+	"github.com/asifrahaman13/bhagabad_gita/internal/core/domain"
+	service "github.com/asifrahaman13/bhagabad_gita/internal/core/services"
+	"github.com/asifrahaman13/bhagabad_gita/internal/repository"
+	"github.com/google/uuid"
+	"github.com/qdrant/go-client/qdrant"
+	"os"
+	"strings"
+)
+
+const VersesPath = "static/verses.json"
+
+// VerseCorpus is the layout of VersesPath.
+type VerseCorpus struct {
+	Chapters []domain.Chapter `json:"chapters"`
+	Verses   []domain.Verse   `json:"verses"`
+}
+
+func LoadVerseCorpus(path string) VerseCorpus {
+	data, err := os.ReadFile(path)
+	ErrorHandler(err)
+	var corpus VerseCorpus
+	err = json.Unmarshal(data, &corpus)
+	ErrorHandler(err)
+	return corpus
+}
+
+// IngestVerses stores chapters and verses in the MongoDB verses and chapters
+// collections used by the /v1/chapters and /v1/verses endpoints.
+func IngestVerses(conf *config.Config, corpus VerseCorpus) {
+	conn, err := repository.Connect(context.Background(), conf.Mongo)
+	ErrorHandler(err)
+	defer conn.Disconnect(context.Background())
+	verseRepo := repository.VerseRepo.Initialize(repository.NewMongoStore(conn))
+	err = service.InitializeVerseService(verseRepo).Import(context.Background(), corpus.Chapters, corpus.Verses)
+	ErrorHandler(err)
+	fmt.Printf("Stored %d chapters and %d verses\n", len(corpus.Chapters), len(corpus.Verses))
+}
+
+// UpsertVerseEmbeddings embeds each verse with its chapter and verse number
+// in the payload, so that searches can be filtered by chapter.
+func (q *QdrantService) UpsertVerseEmbeddings(verses []domain.Verse, embeddingService *EmbeddingService) {
+	var points []*qdrant.PointStruct
+	for _, verse := range verses {
+		content := strings.TrimSpace(verse.Translations["en"] + " " + verse.Commentary["en"])
+		embedding, err := embeddingService.GetEmbedding(content)
+		if err != nil {
+			fmt.Printf("Error getting embedding for verse %d.%d: %v\n", verse.Chapter, verse.Verse, err)
+			continue
+		}
+		points = append(points, &qdrant.PointStruct{
+			Id:      qdrant.NewIDUUID(uuid.New().String()),
+			Vectors: qdrant.NewVectors(embedding...),
+			Payload: qdrant.NewValueMap(map[string]any{
+				"pageContent": content,
+				"chapter":     verse.Chapter,
+				"verse":       verse.Verse,
+			}),
+		})
+	}
+	q.createCollection(points)
+	operationInfo, err := q.client.Upsert(context.Background(), &qdrant.UpsertPoints{
+		CollectionName: q.collection,
+		Points:         points,
+	})
+	ErrorHandler(err)
+	fmt.Println("Upsert operation successful:", operationInfo)
+}